@@ -2,72 +2,366 @@ package activities
 
 import (
 	"context"
+	"crypto/sha256"
+	"crypto/tls"
+	"encoding/hex"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
+	"math"
+	"math/rand"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"syscall"
 	"time"
 
 	"go.temporal.io/sdk/activity"
 	"go.temporal.io/sdk/log"
+	"go.temporal.io/sdk/temporal"
 
 	"myproject/restclient" // Replace with your actual module path
 )
 
 // RESTServiceRequest represents input for REST service activities
 type RESTServiceRequest struct {
-	ServiceName string                     `json:"service_name"`
-	BaseURL     string                     `json:"base_url"`
-	Auth        restclient.AuthConfig      `json:"auth"`
-	Request     restclient.RESTRequest     `json:"request"`
-	Retry       *RetryConfig               `json:"retry,omitempty"`
-	Timeout     time.Duration              `json:"timeout,omitempty"`
+	ServiceName string                 `json:"service_name"`
+	BaseURL     string                 `json:"base_url"`
+	Auth        restclient.AuthConfig  `json:"auth"`
+	Request     restclient.RESTRequest `json:"request"`
+	Retry       *RetryConfig           `json:"retry,omitempty"`
+	Timeout     time.Duration          `json:"timeout,omitempty"`
+
+	// Name of a registered transform applied to successful response bodies
+	ResponseTransform string `json:"response_transform,omitempty"`
+	// Include a sanitized copy of the sent request in the response
+	CaptureSentRequest bool `json:"capture_sent_request,omitempty"`
+	// Bounds the whole retry sequence including backoffs; Timeout bounds each attempt
+	OverallTimeout time.Duration `json:"overall_timeout,omitempty"`
+	// Name of a registered success predicate overriding the default 2xx check
+	SuccessPredicate string `json:"success_predicate,omitempty"`
+	// Status codes treated as failures even within 2xx, e.g. 206 Partial Content.
+	// Add them to RetryConfig.RetryableStatusCodes to retry them.
+	FailureStatusCodes []int `json:"failure_status_codes,omitempty"`
+	// Name of a registered error classifier. Permanent failures it reports are returned as
+	// non-retryable application errors of type PermanentFailureErrorType.
+	Classifier string `json:"classifier,omitempty"`
 }
 
 // RESTServiceResponse represents output from REST service activities
 type RESTServiceResponse struct {
-	ServiceName   string                  `json:"service_name"`
-	StatusCode    int                     `json:"status_code"`
-	Status        string                  `json:"status"`
-	Headers       map[string][]string     `json:"headers"`
-	Body          string                  `json:"body"`
-	ContentType   string                  `json:"content_type"`
-	Duration      time.Duration           `json:"duration"`
-	URL           string                  `json:"url"`
-	Success       bool                    `json:"success"`
-	ErrorMessage  string                  `json:"error_message,omitempty"`
-	Retries       int                     `json:"retries,omitempty"`
+	ServiceName  string              `json:"service_name"`
+	StatusCode   int                 `json:"status_code"`
+	Status       string              `json:"status"`
+	Headers      map[string][]string `json:"headers"`
+	Body         string              `json:"body"`
+	ContentType  string              `json:"content_type"`
+	Duration     time.Duration       `json:"duration"`
+	URL          string              `json:"url"`
+	Success      bool                `json:"success"`
+	ErrorMessage string              `json:"error_message,omitempty"`
+	Retries      int                 `json:"retries,omitempty"`
+	SentRequest  *SentRequest        `json:"sent_request,omitempty"`
+
+	// AttemptsLog records every attempt made by InvokeRESTServiceWithRetry
+	AttemptsLog []AttemptRecord `json:"attempts_log,omitempty"`
 }
 
+// UnmarshalData unmarshals the "data" field of an enveloped JSON body into v.
+// Use restclient.UnmarshalEnvelope for APIs with a different envelope key.
+func (r *RESTServiceResponse) UnmarshalData(v interface{}) error {
+	return restclient.UnmarshalEnvelope([]byte(r.Body), restclient.DefaultEnvelopeKey, v)
+}
+
+// SentRequest is a sanitized copy of the request sent to a REST service
+type SentRequest struct {
+	Method  string              `json:"method"`
+	URL     string              `json:"url"`
+	Headers map[string][]string `json:"headers"`
+	Body    string              `json:"body,omitempty"`
+}
+
+// AttemptRecord is the outcome of a single attempt of a retried REST call
+type AttemptRecord struct {
+	Attempt    int           `json:"attempt"`
+	StatusCode int           `json:"status_code,omitempty"` // Zero when no response was received
+	Error      string        `json:"error,omitempty"`
+	Duration   time.Duration `json:"duration"`
+}
+
+// redactedValue replaces secrets in captured requests
+const redactedValue = "[REDACTED]"
+
 // RetryConfig defines retry behavior for REST calls
 type RetryConfig struct {
-	MaxAttempts        int           `json:"max_attempts"`
-	InitialBackoff     time.Duration `json:"initial_backoff"`
-	BackoffMultiplier  float64       `json:"backoff_multiplier"`
-	MaxBackoff         time.Duration `json:"max_backoff"`
-	RetryableStatusCodes []int       `json:"retryable_status_codes,omitempty"` // Default: 5xx errors
+	MaxAttempts          int           `json:"max_attempts"`
+	InitialBackoff       time.Duration `json:"initial_backoff"`
+	BackoffMultiplier    float64       `json:"backoff_multiplier"`
+	MaxBackoff           time.Duration `json:"max_backoff"`                      // A longer Retry-After stops retrying
+	RetryableStatusCodes []int         `json:"retryable_status_codes,omitempty"` // Default: 5xx errors
+	RetryAfterJitter     float64       `json:"retry_after_jitter,omitempty"`     // Fraction added on top of Retry-After, e.g. 0.2
+	BackoffFunc          string        `json:"backoff_func,omitempty"`           // Default: exponential
+	TotalTimeout         time.Duration `json:"total_timeout,omitempty"`          // Retry budget; no retry starts once it would be exceeded
+
+	// Transport errors are retried only when their message contains one of these,
+	// e.g. "connection reset". Default: every transport error is retried.
+	RetryableErrorSubstrings []string `json:"retryable_error_substrings,omitempty"`
+}
+
+// Named backoff progressions for RetryConfig.BackoffFunc
+const (
+	ConstantBackoff    = "constant"
+	LinearBackoff      = "linear"
+	ExponentialBackoff = "exponential"
+	FibonacciBackoff   = "fibonacci"
+)
+
+// BatchOutcome represents the aggregate result of a batch of REST calls
+type BatchOutcome string
+
+const (
+	AllSucceeded   BatchOutcome = "all_succeeded"
+	PartialSuccess BatchOutcome = "partial_success"
+	AllFailed      BatchOutcome = "all_failed"
+)
+
+// BatchSummary represents output from the batch summary activity
+type BatchSummary struct {
+	Outcome    BatchOutcome           `json:"outcome"`
+	Total      int                    `json:"total"`
+	Successful int                    `json:"successful"`
+	Failed     int                    `json:"failed"`
+	Responses  []*RESTServiceResponse `json:"responses"`
+}
+
+// ResponseTransformFunc reshapes a response body before it is returned to the workflow
+type ResponseTransformFunc func(body string) (string, error)
+
+// SuccessPredicateFunc decides whether a response counts as successful
+type SuccessPredicateFunc func(resp *restclient.RESTResponse) bool
+
+// ErrorClassifierFunc turns a response into an error. A nil error leaves the response
+// unclassified; otherwise retryable decides whether Temporal may retry the activity.
+type ErrorClassifierFunc func(resp *RESTServiceResponse) (retryable bool, err error)
+
+// PermanentFailureErrorType is the application error type of responses an error
+// classifier marks as non-retryable
+const PermanentFailureErrorType = "PermanentFailure"
+
+// Built-in response transforms
+const (
+	ExtractDataFieldTransform  = "extract_data_field"
+	FirstArrayElementTransform = "first_array_element"
+)
+
+// Registry holds the named functions RESTServiceRequest refers to by name, since functions
+// cannot be serialized into a request. Register functions before the worker starts; the
+// registry is not guarded for concurrent writes.
+type Registry struct {
+	transforms  map[string]ResponseTransformFunc
+	predicates  map[string]SuccessPredicateFunc
+	classifiers map[string]ErrorClassifierFunc
+}
+
+// NewRegistry creates a registry holding the built-in response transforms
+func NewRegistry() *Registry {
+	return &Registry{
+		transforms: map[string]ResponseTransformFunc{
+			ExtractDataFieldTransform:  extractDataField,
+			FirstArrayElementTransform: firstArrayElement,
+		},
+		predicates:  make(map[string]SuccessPredicateFunc),
+		classifiers: make(map[string]ErrorClassifierFunc),
+	}
+}
+
+// RegisterResponseTransform registers a named response transform
+func (r *Registry) RegisterResponseTransform(name string, transform ResponseTransformFunc) {
+	r.transforms[name] = transform
+}
+
+// RegisterSuccessPredicate registers a named success predicate
+func (r *Registry) RegisterSuccessPredicate(name string, predicate SuccessPredicateFunc) {
+	r.predicates[name] = predicate
+}
+
+// RegisterErrorClassifier registers a named error classifier
+func (r *Registry) RegisterErrorClassifier(name string, classifier ErrorClassifierFunc) {
+	r.classifiers[name] = classifier
 }
 
-// RESTServiceActivities contains REST service related activities
+// ClientCache holds REST clients reused across activity calls, keyed by base URL and
+// auth config, so OAuth2 tokens are shared between calls
+type ClientCache struct {
+	mu      sync.Mutex
+	clients map[string]*restclient.RESTClient
+}
+
+// NewClientCache creates an empty client cache
+func NewClientCache() *ClientCache {
+	return &ClientCache{clients: make(map[string]*restclient.RESTClient)}
+}
+
+// Clear drops all cached REST clients and their tokens
+func (c *ClientCache) Clear() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.clients = make(map[string]*restclient.RESTClient)
+}
+
+// RESTServiceActivities contains REST service related activities. Every exported method is
+// an activity, so the struct can be registered with worker.RegisterActivity as a whole;
+// configure it through RESTServiceActivitiesOptions instead. A zero value uses the defaults.
 type RESTServiceActivities struct {
-	logger log.Logger
+	logger   log.Logger
+	registry *Registry
+	clients  *ClientCache
+
+	// Fills in unset fields on first use, so a zero value works
+	defaultsOnce sync.Once
+
+	// Compiled body patterns reused across ValidateBodyPattern calls
+	patternsMu sync.Mutex
+	patterns   map[string]*regexp.Regexp
+
+	metrics *Metrics
+
+	// Upper bound for RetryConfig.MaxAttempts
+	maxAttemptsCap int
+
+	// Upper bound for response bodies embedded in error messages and logs
+	maxLoggedBodyBytes int
+}
+
+// DefaultMaxAttemptsCap bounds RetryConfig.MaxAttempts unless MaxAttemptsCap is set
+const DefaultMaxAttemptsCap = 10
+
+// RESTServiceActivitiesOptions configures NewRESTServiceActivitiesWithOptions.
+// Zero values keep the defaults.
+type RESTServiceActivitiesOptions struct {
+	// Registry supplies named transforms, predicates and classifiers. Default: built-ins only
+	Registry *Registry
+
+	// ClientCache lets the caller share or clear cached clients. Default: a private cache
+	ClientCache *ClientCache
+
+	// Metrics receives REST call metrics, e.g. for export by the caller. Default: a private instance
+	Metrics *Metrics
+
+	// MaxAttemptsCap is the upper bound RetryConfig.MaxAttempts is clamped to. Default: DefaultMaxAttemptsCap
+	MaxAttemptsCap int
+
+	// MaxLoggedBodyBytes bounds how much of a failed response's body is embedded in
+	// RESTServiceResponse.ErrorMessage and logs. Default: restclient.DefaultMaxLoggedBodyBytes
+	MaxLoggedBodyBytes int
 }
 
 // NewRESTServiceActivities creates new instance of REST service activities
 func NewRESTServiceActivities(logger log.Logger) *RESTServiceActivities {
-	return &RESTServiceActivities{
-		logger: logger,
+	return NewRESTServiceActivitiesWithOptions(logger, RESTServiceActivitiesOptions{})
+}
+
+// NewRESTServiceActivitiesWithOptions creates REST service activities configured by options
+func NewRESTServiceActivitiesWithOptions(logger log.Logger, options RESTServiceActivitiesOptions) *RESTServiceActivities {
+	a := &RESTServiceActivities{
+		logger:   logger,
+		registry: options.Registry,
+		clients:  options.ClientCache,
+		metrics:  options.Metrics,
+
+		maxAttemptsCap:     options.MaxAttemptsCap,
+		maxLoggedBodyBytes: options.MaxLoggedBodyBytes,
+	}
+	a.applyDefaults()
+	return a
+}
+
+// applyDefaults fills in every field left unset. Methods using those fields call it first,
+// so an uninitialized &RESTServiceActivities{} behaves like NewRESTServiceActivities(nil).
+func (a *RESTServiceActivities) applyDefaults() {
+	a.defaultsOnce.Do(func() {
+		if a.registry == nil {
+			a.registry = NewRegistry()
+		}
+		if a.clients == nil {
+			a.clients = NewClientCache()
+		}
+		if a.patterns == nil {
+			a.patterns = make(map[string]*regexp.Regexp)
+		}
+		if a.metrics == nil {
+			a.metrics = NewMetrics()
+		}
+		if a.maxAttemptsCap < 1 {
+			a.maxAttemptsCap = DefaultMaxAttemptsCap
+		}
+		if a.maxLoggedBodyBytes < 1 {
+			a.maxLoggedBodyBytes = restclient.DefaultMaxLoggedBodyBytes
+		}
+	})
+}
+
+// getClient returns a cached REST client for the service, creating one if needed.
+// Reusing clients lets OAuth2 tokens be shared across activity calls. Configs with a
+// credential or token provider are never cached: providers aren't part of the cache key,
+// so a cached client could hand one caller's credentials to another.
+func (a *RESTServiceActivities) getClient(baseURL string, auth restclient.AuthConfig) (*restclient.RESTClient, error) {
+	a.applyDefaults()
+
+	if auth.CredentialProvider != nil || auth.TokenProvider != nil {
+		return restclient.NewRESTClient(baseURL, auth)
+	}
+
+	key, err := clientCacheKey(baseURL, auth)
+	if err != nil {
+		return nil, err
+	}
+
+	a.clients.mu.Lock()
+	defer a.clients.mu.Unlock()
+
+	if client, ok := a.clients.clients[key]; ok {
+		return client, nil
 	}
+
+	client, err := restclient.NewRESTClient(baseURL, auth)
+	if err != nil {
+		return nil, err
+	}
+	a.clients.clients[key] = client
+	return client, nil
+}
+
+// clientCacheKey builds the cache key from the base URL and a hash of the auth config
+func clientCacheKey(baseURL string, auth restclient.AuthConfig) (string, error) {
+	authJSON, err := json.Marshal(auth)
+	if err != nil {
+		return "", fmt.Errorf("failed to hash auth config: %w", err)
+	}
+	sum := sha256.Sum256(authJSON)
+	return baseURL + "|" + hex.EncodeToString(sum[:]), nil
 }
 
 // InvokeRESTService executes a REST API call
 func (a *RESTServiceActivities) InvokeRESTService(ctx context.Context, req RESTServiceRequest) (*RESTServiceResponse, error) {
+	a.applyDefaults()
+
 	logger := activity.GetLogger(ctx)
 	logger.Info("Invoking REST service",
 		"service", req.ServiceName,
 		"method", req.Request.Method,
 		"endpoint", req.Request.Endpoint)
 
-	// Create REST client
-	client, err := restclient.NewRESTClient(req.BaseURL, req.Auth)
+	// Get a cached REST client
+	client, err := a.getClient(req.BaseURL, req.Auth)
 	if err != nil {
 		logger.Error("Failed to create REST client", "error", err)
 		return &RESTServiceResponse{
@@ -85,6 +379,7 @@ func (a *RESTServiceActivities) InvokeRESTService(ctx context.Context, req RESTS
 	// Execute REST call
 	resp, err := client.Execute(ctx, req.Request)
 	if err != nil {
+		a.metrics.recordError()
 		logger.Error("REST call failed", "error", err)
 		return &RESTServiceResponse{
 			ServiceName:  req.ServiceName,
@@ -93,6 +388,28 @@ func (a *RESTServiceActivities) InvokeRESTService(ctx context.Context, req RESTS
 		}, err
 	}
 
+	a.metrics.recordResponse(resp.StatusCode, resp.Duration)
+
+	// Determine success, using the registered predicate if requested
+	success := resp.IsSuccess()
+	if req.SuccessPredicate != "" {
+		predicate, exists := a.registry.predicates[req.SuccessPredicate]
+		if !exists {
+			err := fmt.Errorf("unknown success predicate: %s", req.SuccessPredicate)
+			logger.Error("REST call failed", "error", err)
+			return &RESTServiceResponse{
+				ServiceName:  req.ServiceName,
+				Success:      false,
+				ErrorMessage: err.Error(),
+			}, err
+		}
+		success = predicate(resp)
+	}
+	rejectedStatus := success && containsStatusCode(req.FailureStatusCodes, resp.StatusCode)
+	if rejectedStatus {
+		success = false
+	}
+
 	// Build response
 	result := &RESTServiceResponse{
 		ServiceName: req.ServiceName,
@@ -103,15 +420,45 @@ func (a *RESTServiceActivities) InvokeRESTService(ctx context.Context, req RESTS
 		ContentType: resp.ContentType,
 		Duration:    resp.Duration,
 		URL:         resp.URL,
-		Success:     resp.IsSuccess(),
+		Success:     success,
+	}
+
+	// Capture sent request
+	if req.CaptureSentRequest && resp.Request != nil {
+		result.SentRequest = sanitizeSentRequest(resp.Request, req.Request.Body, req.Auth)
+	}
+
+	// Apply response transform
+	if result.Success && req.ResponseTransform != "" {
+		if err := a.applyResponseTransform(req.ResponseTransform, result); err != nil {
+			logger.Error("Response transform failed",
+				"service", req.ServiceName,
+				"transform", req.ResponseTransform,
+				"error", err)
+			result.Success = false
+			result.ErrorMessage = err.Error()
+			return result, err
+		}
 	}
 
 	if !result.Success {
 		result.ErrorMessage = fmt.Sprintf("HTTP %d: %s", resp.StatusCode, resp.Status)
+		if req.SuccessPredicate != "" && resp.IsSuccess() {
+			result.ErrorMessage = fmt.Sprintf("HTTP %d: rejected by success predicate '%s'", resp.StatusCode, req.SuccessPredicate)
+		}
+		if rejectedStatus {
+			result.ErrorMessage = fmt.Sprintf("HTTP %d: configured as a failure status", resp.StatusCode)
+		}
+		// Error bodies often explain the failure, but may be arbitrarily large
+		body := restclient.TruncateBody(resp.Body, a.maxLoggedBodyBytes)
+		if body != "" {
+			result.ErrorMessage = fmt.Sprintf("%s: %s", result.ErrorMessage, body)
+		}
 		logger.Warn("REST service call failed",
 			"service", req.ServiceName,
 			"status_code", resp.StatusCode,
-			"status", resp.Status)
+			"status", resp.Status,
+			"body", body)
 	} else {
 		logger.Info("REST service call successful",
 			"service", req.ServiceName,
@@ -119,11 +466,53 @@ func (a *RESTServiceActivities) InvokeRESTService(ctx context.Context, req RESTS
 			"duration", resp.Duration)
 	}
 
+	if req.Classifier != "" {
+		return a.classifyResponse(logger, req, result)
+	}
+
 	return result, nil
 }
 
-// InvokeRESTServiceWithRetry executes REST API call with retry logic
-func (a *RESTServiceActivities) InvokeRESTServiceWithRetry(ctx context.Context, req RESTServiceRequest) (*RESTServiceResponse, error) {
+// classifyResponse applies the request's error classifier, returning a non-retryable
+// application error for permanent failures so Temporal does not retry them
+func (a *RESTServiceActivities) classifyResponse(logger log.Logger, req RESTServiceRequest, result *RESTServiceResponse) (*RESTServiceResponse, error) {
+	a.applyDefaults()
+
+	classifier, exists := a.registry.classifiers[req.Classifier]
+	if !exists {
+		err := fmt.Errorf("unknown error classifier: %s", req.Classifier)
+		logger.Error("REST call failed", "error", err)
+		result.Success = false
+		result.ErrorMessage = err.Error()
+		return result, err
+	}
+
+	retryable, err := classifier(result)
+	if err == nil {
+		return result, nil
+	}
+
+	result.Success = false
+	result.ErrorMessage = err.Error()
+	logger.Warn("REST response classified as failure",
+		"service", req.ServiceName,
+		"classifier", req.Classifier,
+		"status_code", result.StatusCode,
+		"retryable", retryable,
+		"error", err)
+
+	if retryable {
+		return result, err
+	}
+	return result, temporal.NewNonRetryableApplicationError(err.Error(), PermanentFailureErrorType, err)
+}
+
+// InvokeRESTServiceWithRetry executes REST API call with retry logic.
+// Each attempt is recorded in the response's AttemptsLog, and a single summary line
+// is logged once the call finishes, however it ends.
+func (a *RESTServiceActivities) InvokeRESTServiceWithRetry(ctx context.Context, req RESTServiceRequest) (result *RESTServiceResponse, err error) {
+	a.applyDefaults()
+
 	logger := activity.GetLogger(ctx)
 
 	// Set default retry config
@@ -133,12 +522,11 @@ func (a *RESTServiceActivities) InvokeRESTServiceWithRetry(ctx context.Context,
 		BackoffMultiplier:    2.0,
 		MaxBackoff:           30 * time.Second,
 		RetryableStatusCodes: []int{500, 502, 503, 504, 429}, // Server errors and rate limiting
+		BackoffFunc:          ExponentialBackoff,
 	}
 
 	if req.Retry != nil {
-		if req.Retry.MaxAttempts > 0 {
-			retryConfig.MaxAttempts = req.Retry.MaxAttempts
-		}
+		retryConfig.MaxAttempts = a.clampMaxAttempts(logger, req.ServiceName, req.Retry.MaxAttempts)
 		if req.Retry.InitialBackoff > 0 {
 			retryConfig.InitialBackoff = req.Retry.InitialBackoff
 		}
@@ -151,6 +539,22 @@ func (a *RESTServiceActivities) InvokeRESTServiceWithRetry(ctx context.Context,
 		if len(req.Retry.RetryableStatusCodes) > 0 {
 			retryConfig.RetryableStatusCodes = req.Retry.RetryableStatusCodes
 		}
+		if req.Retry.RetryAfterJitter > 0 {
+			retryConfig.RetryAfterJitter = req.Retry.RetryAfterJitter
+		}
+		if req.Retry.BackoffFunc != "" {
+			retryConfig.BackoffFunc = req.Retry.BackoffFunc
+		}
+		if req.Retry.TotalTimeout > 0 {
+			retryConfig.TotalTimeout = req.Retry.TotalTimeout
+		}
+		if len(req.Retry.RetryableErrorSubstrings) > 0 {
+			retryConfig.RetryableErrorSubstrings = req.Retry.RetryableErrorSubstrings
+		}
+	}
+
+	if !isKnownBackoffFunc(retryConfig.BackoffFunc) {
+		return nil, fmt.Errorf("unknown backoff function: %s", retryConfig.BackoffFunc)
 	}
 
 	logger.Info("Invoking REST service with retry",
@@ -158,18 +562,37 @@ func (a *RESTServiceActivities) InvokeRESTServiceWithRetry(ctx context.Context,
 		"max_attempts", retryConfig.MaxAttempts,
 		"initial_backoff", retryConfig.InitialBackoff)
 
+	if req.OverallTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, req.OverallTimeout)
+		defer cancel()
+	}
+
 	var lastResponse *RESTServiceResponse
 	var lastError error
-	backoff := retryConfig.InitialBackoff
+	var attempts []AttemptRecord
+	start := time.Now()
+
+	defer func() {
+		if result != nil {
+			result.AttemptsLog = attempts
+		}
+		logAttemptSummary(logger, req, result, err, attempts, time.Since(start))
+	}()
 
 	for attempt := 1; attempt <= retryConfig.MaxAttempts; attempt++ {
+		if attempt > 1 {
+			a.metrics.recordRetry()
+		}
 		logger.Info("REST service attempt",
 			"service", req.ServiceName,
 			"attempt", attempt,
 			"of", retryConfig.MaxAttempts)
 
 		// Execute the request
+		attemptStart := time.Now()
 		resp, err := a.InvokeRESTService(ctx, req)
+		attempts = append(attempts, newAttemptRecord(attempt, resp, err, time.Since(attemptStart)))
 
 		if err == nil && resp.Success {
 			resp.Retries = attempt - 1
@@ -187,29 +610,87 @@ func (a *RESTServiceActivities) InvokeRESTServiceWithRetry(ctx context.Context,
 			resp.Retries = attempt - 1
 			return resp, nil
 		}
+		var appErr *temporal.ApplicationError
+		if errors.As(err, &appErr) && appErr.NonRetryable() {
+			logger.Warn("Permanent failure, stopping",
+				"service", req.ServiceName,
+				"error", err)
+			if resp != nil {
+				resp.Retries = attempt - 1
+			}
+			return resp, err
+		}
+		if err != nil && !isRetryableError(err, retryConfig.RetryableErrorSubstrings) {
+			logger.Warn("Non-retryable error, stopping",
+				"service", req.ServiceName,
+				"error", err)
+			if resp != nil {
+				resp.Retries = attempt - 1
+			}
+			return resp, err
+		}
 
 		lastResponse = resp
 		lastError = err
 
 		// Don't sleep after the last attempt
 		if attempt < retryConfig.MaxAttempts {
+			wait := backoffDuration(retryConfig, attempt)
+			if resp != nil {
+				if retryAfter, ok := parseRetryAfter(resp.Headers); ok {
+					// Never retry before the server asked to; stop instead when it asks for
+					// longer than MaxBackoff or the remaining overall timeout
+					limit := retryConfig.MaxBackoff
+					if deadline, ok := ctx.Deadline(); ok && req.OverallTimeout > 0 {
+						if remaining := time.Until(deadline); limit <= 0 || remaining < limit {
+							limit = remaining
+						}
+					}
+					if limit > 0 && retryAfter > limit {
+						logger.Error("Retry-After exceeds the retry limit, stopping retries",
+							"service", req.ServiceName,
+							"attempts", attempt,
+							"retry_after", retryAfter,
+							"limit", limit)
+						resp.Retries = attempt - 1
+						return resp, fmt.Errorf("%s requested Retry-After %s, longer than the %s retry limit, after %d of %d attempts",
+							req.ServiceName, retryAfter, limit.Round(time.Millisecond), attempt, retryConfig.MaxAttempts)
+					}
+					wait = retryAfterWait(retryAfter, retryConfig.RetryAfterJitter, limit, nil)
+				}
+			}
+
+			// Stop early rather than sleep past the retry budget
+			if retryConfig.TotalTimeout > 0 && time.Since(start)+wait > retryConfig.TotalTimeout {
+				logger.Error("Retry budget exhausted, stopping retries",
+					"service", req.ServiceName,
+					"attempts", attempt,
+					"total_timeout", retryConfig.TotalTimeout)
+				if lastResponse != nil {
+					lastResponse.Retries = attempt - 1
+				}
+				return lastResponse, fmt.Errorf("retry total timeout %s exceeded after %d of %d attempts (elapsed %s, next backoff %s)",
+					retryConfig.TotalTimeout, attempt, retryConfig.MaxAttempts, time.Since(start).Round(time.Millisecond), wait)
+			}
+
 			logger.Warn("Attempt failed, retrying",
 				"service", req.ServiceName,
 				"attempt", attempt,
-				"backoff", backoff)
+				"backoff", wait)
 
 			select {
-			case <-time.After(backoff):
+			case <-time.After(wait):
 				// Continue to next attempt
 			case <-ctx.Done():
+				if req.OverallTimeout > 0 && errors.Is(ctx.Err(), context.DeadlineExceeded) {
+					logger.Error("Overall timeout exceeded, stopping retries",
+						"service", req.ServiceName,
+						"attempts", attempt,
+						"overall_timeout", req.OverallTimeout)
+					return lastResponse, fmt.Errorf("overall timeout %s exceeded after %d attempts: %w", req.OverallTimeout, attempt, ctx.Err())
+				}
 				return nil, ctx.Err()
 			}
-
-			// Calculate next backoff with cap
-			backoff = time.Duration(float64(backoff) * retryConfig.BackoffMultiplier)
-			if backoff > retryConfig.MaxBackoff {
-				backoff = retryConfig.MaxBackoff
-			}
 		}
 	}
 
@@ -296,6 +777,86 @@ func (a *RESTServiceActivities) PatchResource(ctx context.Context, serviceName,
 	return a.InvokeRESTService(ctx, req)
 }
 
+// mergePatchContentType is the media type of JSON merge patches (RFC 7396)
+const mergePatchContentType = "application/merge-patch+json"
+
+// PatchResourcePartial performs HTTP PATCH with a sparse JSON merge patch built from body.
+// Fields holding their zero value (nil, false, 0, "", or an empty array or object) are
+// omitted, recursively, so only fields that were set are changed. Because of this a field
+// cannot be cleared to its zero value; use PatchResource with an explicit body for that.
+func (a *RESTServiceActivities) PatchResourcePartial(ctx context.Context, serviceName, baseURL, endpoint string, auth restclient.AuthConfig, body interface{}) (*RESTServiceResponse, error) {
+	patch, err := sparsePatch(body)
+	if err != nil {
+		// Retrying cannot fix a body that isn't a JSON object
+		return nil, temporal.NewNonRetryableApplicationError(
+			fmt.Sprintf("invalid patch body: %v", err),
+			"InvalidArgument",
+			err)
+	}
+
+	req := RESTServiceRequest{
+		ServiceName: serviceName,
+		BaseURL:     baseURL,
+		Auth:        auth,
+		Request: restclient.RESTRequest{
+			Method:   restclient.PATCH,
+			Endpoint: endpoint,
+			Headers:  map[string]string{"Content-Type": mergePatchContentType},
+			Body:     patch,
+		},
+	}
+
+	return a.InvokeRESTService(ctx, req)
+}
+
+// sparsePatch round-trips body through JSON and drops zero-valued fields
+func sparsePatch(body interface{}) (map[string]interface{}, error) {
+	encoded, err := json.Marshal(body)
+	if err != nil {
+		return nil, err
+	}
+
+	var fields map[string]interface{}
+	if err := json.Unmarshal(encoded, &fields); err != nil {
+		return nil, fmt.Errorf("body must encode to a JSON object: %w", err)
+	}
+	pruneZeroValues(fields)
+	return fields, nil
+}
+
+// pruneZeroValues removes zero-valued entries from a decoded JSON object, including
+// nested objects left empty once their own zero values are removed
+func pruneZeroValues(fields map[string]interface{}) {
+	for key, value := range fields {
+		if nested, ok := value.(map[string]interface{}); ok {
+			pruneZeroValues(nested)
+		}
+		if isZeroJSONValue(fields[key]) {
+			delete(fields, key)
+		}
+	}
+}
+
+// isZeroJSONValue reports whether a decoded JSON value is the zero value of its type
+func isZeroJSONValue(value interface{}) bool {
+	switch v := value.(type) {
+	case nil:
+		return true
+	case bool:
+		return !v
+	case float64:
+		return v == 0
+	case string:
+		return v == ""
+	case []interface{}:
+		return len(v) == 0
+	case map[string]interface{}:
+		return len(v) == 0
+	default:
+		return false
+	}
+}
+
 // DeleteResource performs HTTP DELETE operation
 func (a *RESTServiceActivities) DeleteResource(ctx context.Context, serviceName, baseURL, endpoint string, auth restclient.AuthConfig) (*RESTServiceResponse, error) {
 	req := RESTServiceRequest{
@@ -311,85 +872,1048 @@ func (a *RESTServiceActivities) DeleteResource(ctx context.Context, serviceName,
 	return a.InvokeRESTService(ctx, req)
 }
 
-// BatchRESTCalls executes multiple REST calls in sequence
-func (a *RESTServiceActivities) BatchRESTCalls(ctx context.Context, requests []RESTServiceRequest) ([]*RESTServiceResponse, error) {
-	logger := activity.GetLogger(ctx)
-	logger.Info("Executing batch REST calls", "count", len(requests))
+// DeleteResourceWithBody performs HTTP DELETE operation with a request body
+func (a *RESTServiceActivities) DeleteResourceWithBody(ctx context.Context, serviceName, baseURL, endpoint string, auth restclient.AuthConfig, body interface{}) (*RESTServiceResponse, error) {
+	req := RESTServiceRequest{
+		ServiceName: serviceName,
+		BaseURL:     baseURL,
+		Auth:        auth,
+		Request: restclient.RESTRequest{
+			Method:   restclient.DELETE,
+			Endpoint: endpoint,
+			Body:     body,
+		},
+	}
 
-	responses := make([]*RESTServiceResponse, len(requests))
+	return a.InvokeRESTService(ctx, req)
+}
 
-	for i, req := range requests {
-		logger.Info("Executing batch request",
-			"index", i+1,
-			"of", len(requests),
-			"service", req.ServiceName,
-			"endpoint", req.Request.Endpoint)
+// CreateIfNotExistsResponse is the result of CreateIfNotExists
+type CreateIfNotExistsResponse struct {
+	Created  bool                 `json:"created"`  // False when the resource already existed
+	Response *RESTServiceResponse `json:"response"` // The create response, or the existing resource
+}
 
-		resp, err := a.InvokeRESTService(ctx, req)
-		if err != nil {
-			logger.Error("Batch request failed",
-				"index", i+1,
-				"service", req.ServiceName,
-				"error", err)
-			responses[i] = &RESTServiceResponse{
-				ServiceName:  req.ServiceName,
-				Success:      false,
-				ErrorMessage: err.Error(),
-			}
-		} else {
-			responses[i] = resp
-		}
+// CreateIfNotExists GETs getEndpoint and, if it returns 404, POSTs body to createEndpoint.
+// An existing resource is returned as-is with Created false. A 409 Conflict from the create,
+// as when another worker created the resource in between, is resolved by fetching it again.
+// Any other failed response is returned without creating; check Response.Success.
+func (a *RESTServiceActivities) CreateIfNotExists(ctx context.Context, serviceName, baseURL, getEndpoint, createEndpoint string, auth restclient.AuthConfig, body interface{}) (*CreateIfNotExistsResponse, error) {
+	existing, err := a.GetResource(ctx, serviceName, baseURL, getEndpoint, auth, nil)
+	if err != nil {
+		return nil, err
+	}
+	if existing.StatusCode != http.StatusNotFound {
+		return &CreateIfNotExistsResponse{Created: false, Response: existing}, nil
 	}
 
-	// Count results
-	successful := 0
-	failed := 0
-	for _, resp := range responses {
-		if resp.Success {
-			successful++
-		} else {
-			failed++
+	created, err := a.CreateResource(ctx, serviceName, baseURL, createEndpoint, auth, body)
+	if err != nil {
+		return nil, err
+	}
+	if created.StatusCode == http.StatusConflict {
+		activity.GetLogger(ctx).Info("Resource created concurrently, fetching it",
+			"service", serviceName,
+			"endpoint", getEndpoint)
+		existing, err = a.GetResource(ctx, serviceName, baseURL, getEndpoint, auth, nil)
+		if err != nil {
+			return nil, err
 		}
+		return &CreateIfNotExistsResponse{Created: false, Response: existing}, nil
 	}
 
-	logger.Info("Batch REST calls completed",
-		"total", len(requests),
-		"successful", successful,
-		"failed", failed)
+	return &CreateIfNotExistsResponse{Created: created.Success, Response: created}, nil
+}
 
-	return responses, nil
+// GetTyped performs HTTP GET and decodes a successful JSON body into T.
+// The raw response is returned as well for status and header access.
+// Generic functions cannot be registered as activities, so call it from within one.
+func GetTyped[T any](a *RESTServiceActivities, ctx context.Context, serviceName, baseURL, endpoint string, auth restclient.AuthConfig) (T, *RESTServiceResponse, error) {
+	resp, err := a.GetResource(ctx, serviceName, baseURL, endpoint, auth, nil)
+	return decodeTyped[T](resp, err)
 }
 
-// ValidateRESTResponse validates REST response against expected criteria
-func (a *RESTServiceActivities) ValidateRESTResponse(ctx context.Context, response *RESTServiceResponse, expectedStatusCode int, requiredFields []string) error {
+// DeleteTyped performs HTTP DELETE and decodes a successful JSON body into T.
+// An empty body, as with 204 No Content, leaves T at its zero value.
+func DeleteTyped[T any](a *RESTServiceActivities, ctx context.Context, serviceName, baseURL, endpoint string, auth restclient.AuthConfig) (T, *RESTServiceResponse, error) {
+	resp, err := a.DeleteResource(ctx, serviceName, baseURL, endpoint, auth)
+	return decodeTyped[T](resp, err)
+}
+
+// decodeTyped decodes the body of a successful response into T
+func decodeTyped[T any](resp *RESTServiceResponse, err error) (T, *RESTServiceResponse, error) {
+	var result T
+	if err != nil {
+		return result, resp, err
+	}
+	if !resp.Success {
+		return result, resp, fmt.Errorf("%s request failed: %s", resp.ServiceName, resp.ErrorMessage)
+	}
+	if resp.Body == "" {
+		return result, resp, nil
+	}
+	if err := json.Unmarshal([]byte(resp.Body), &result); err != nil {
+		return result, resp, fmt.Errorf("failed to decode %s response: %w", resp.ServiceName, err)
+	}
+	return result, resp, nil
+}
+
+// Pagination conventions followed by CollectAllPages
+const (
+	nextCursorField = "next_cursor" // Body field holding the cursor for the next page
+	hasMoreField    = "has_more"    // Optional body field; false ends pagination
+	cursorParam     = "cursor"      // Query parameter the cursor is sent in
+	pageParam       = "page"        // Query parameter for page-number pagination
+)
+
+// CollectAllPages follows a paginated GET and decodes the itemsField array of every page
+// into a single slice. A non-empty "next_cursor" in the body is sent back as the "cursor"
+// query parameter; otherwise the "page" query parameter is incremented, starting from the
+// request's page or 1. Pagination ends at "has_more": false, an empty page, or a cursor
+// page without a next cursor. If more pages remain after maxPages, the items collected so
+// far are returned with an error.
+func CollectAllPages[T any](ctx context.Context, a *RESTServiceActivities, req RESTServiceRequest, itemsField string, maxPages int) ([]T, error) {
 	logger := activity.GetLogger(ctx)
 
-	// Check status code
-	if expectedStatusCode > 0 && response.StatusCode != expectedStatusCode {
-		return fmt.Errorf("expected status code %d, got %d", expectedStatusCode, response.StatusCode)
+	if maxPages <= 0 {
+		return nil, fmt.Errorf("maxPages must be positive, got %d", maxPages)
 	}
 
-	// Check required fields in JSON response
-	if len(requiredFields) > 0 {
-		var jsonData map[string]interface{}
-		if err := json.Unmarshal([]byte(response.Body), &jsonData); err != nil {
-			return fmt.Errorf("failed to parse JSON response: %v", err)
-		}
+	// Copy the query so the caller's request is never mutated
+	query := make(map[string]string, len(req.Request.QueryParams)+1)
+	for key, value := range req.Request.QueryParams {
+		query[key] = value
+	}
+	req.Request.QueryParams = query
 
-		for _, field := range requiredFields {
-			if _, exists := jsonData[field]; !exists {
-				return fmt.Errorf("required field '%s' not found in response", field)
-			}
+	page := 1
+	value, callerPage := query[pageParam]
+	if callerPage {
+		parsed, err := strconv.Atoi(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s query parameter %q: %w", pageParam, value, err)
 		}
+		page = parsed
 	}
+	// Send the starting page explicitly rather than relying on the server's default
+	query[pageParam] = strconv.Itoa(page)
 
-	logger.Info("REST response validation successful",
+	var items []T
+	for fetched := 1; fetched <= maxPages; fetched++ {
+		activity.RecordHeartbeat(ctx, fetched)
+
+		resp, err := a.InvokeRESTService(ctx, req)
+		if err != nil {
+			return items, err
+		}
+		if !resp.Success {
+			return items, fmt.Errorf("%s page %d failed: %s", req.ServiceName, fetched, resp.ErrorMessage)
+		}
+
+		var body map[string]json.RawMessage
+		if err := json.Unmarshal([]byte(resp.Body), &body); err != nil {
+			return items, fmt.Errorf("failed to decode %s page %d: %w", req.ServiceName, fetched, err)
+		}
+		raw, ok := body[itemsField]
+		if !ok {
+			return items, fmt.Errorf("%s page %d has no %q field", req.ServiceName, fetched, itemsField)
+		}
+		var pageItems []T
+		if err := json.Unmarshal(raw, &pageItems); err != nil {
+			return items, fmt.Errorf("failed to decode %q on %s page %d: %w", itemsField, req.ServiceName, fetched, err)
+		}
+		items = append(items, pageItems...)
+
+		var hasMore *bool
+		if raw, ok := body[hasMoreField]; ok {
+			json.Unmarshal(raw, &hasMore)
+		}
+		var nextCursor string
+		if raw, ok := body[nextCursorField]; ok {
+			json.Unmarshal(raw, &nextCursor)
+		}
+
+		switch {
+		case hasMore != nil && !*hasMore:
+			return items, nil
+		case nextCursor != "":
+			query[cursorParam] = nextCursor
+			// Cursor APIs don't use page numbers unless the caller sent one
+			if !callerPage {
+				delete(query, pageParam)
+			}
+		case query[cursorParam] != "" || len(pageItems) == 0:
+			// A cursor page without a next cursor, or an empty page, is the last one
+			return items, nil
+		default:
+			page++
+			query[pageParam] = strconv.Itoa(page)
+		}
+
+		logger.Debug("Fetching next page",
+			"service", req.ServiceName,
+			"pages", fetched,
+			"items", len(items))
+	}
+
+	return items, fmt.Errorf("%s has more pages after %d pages", req.ServiceName, maxPages)
+}
+
+// uploadHeartbeatInterval is how often UploadStream heartbeats while sending
+const uploadHeartbeatInterval = 5 * time.Second
+
+// UploadStream streams a local file to the service with HTTP PUT, heartbeating as bytes are sent
+func (a *RESTServiceActivities) UploadStream(ctx context.Context, serviceName, baseURL, endpoint string, auth restclient.AuthConfig, localFilePath, contentType string) (*RESTServiceResponse, error) {
+	logger := activity.GetLogger(ctx)
+
+	file, err := os.Open(localFilePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open upload file: %w", err)
+	}
+	defer file.Close()
+
+	info, err := file.Stat()
+	if err != nil {
+		return nil, fmt.Errorf("failed to stat upload file: %w", err)
+	}
+
+	logger.Info("Streaming upload",
+		"service", serviceName,
+		"endpoint", endpoint,
+		"file", localFilePath,
+		"size", info.Size())
+
+	req := RESTServiceRequest{
+		ServiceName: serviceName,
+		BaseURL:     baseURL,
+		Auth:        auth,
+		Request: restclient.RESTRequest{
+			Method:   restclient.PUT,
+			Endpoint: endpoint,
+			Headers:  map[string]string{"Content-Type": contentType},
+			Body:     &heartbeatReader{ctx: ctx, reader: file, interval: uploadHeartbeatInterval},
+		},
+	}
+
+	return a.InvokeRESTService(ctx, req)
+}
+
+// heartbeatReader records activity heartbeats with the bytes read so far
+type heartbeatReader struct {
+	ctx      context.Context
+	reader   io.Reader
+	interval time.Duration
+	sent     int64
+	last     time.Time
+}
+
+func (r *heartbeatReader) Read(p []byte) (int, error) {
+	n, err := r.reader.Read(p)
+	r.sent += int64(n)
+
+	if now := time.Now(); now.Sub(r.last) >= r.interval || err == io.EOF {
+		activity.RecordHeartbeat(r.ctx, r.sent)
+		r.last = now
+	}
+	return n, err
+}
+
+// InvokeAndPoll issues the request and, when the service answers 202 Accepted, GETs the
+// status resource named by the Location header every pollInterval until completePredicate
+// reports done or maxPolls is reached, heartbeating between polls. The Location must be on
+// the same scheme and host as the request, since polls carry the request's credentials. A
+// response other than 202 to the initial request is returned as-is. The predicate is a Go
+// func, so call this from within an activity rather than registering it directly.
+func (a *RESTServiceActivities) InvokeAndPoll(ctx context.Context, req RESTServiceRequest, pollInterval time.Duration, completePredicate func(*RESTServiceResponse) bool, maxPolls int) (*RESTServiceResponse, error) {
+	logger := activity.GetLogger(ctx)
+
+	if maxPolls <= 0 {
+		return nil, fmt.Errorf("maxPolls must be positive, got %d", maxPolls)
+	}
+
+	resp, err := a.InvokeRESTService(ctx, req)
+	if err != nil {
+		return resp, err
+	}
+	if resp.StatusCode != http.StatusAccepted {
+		return resp, nil
+	}
+
+	// Resolve the status location against the URL that accepted the request
+	location := http.Header(resp.Headers).Get("Location")
+	if location == "" {
+		return resp, fmt.Errorf("%s returned 202 Accepted without a Location header", req.ServiceName)
+	}
+	statusURL, err := resolveLocation(resp.URL, location)
+	if err != nil {
+		return resp, err
+	}
+
+	pollReq := RESTServiceRequest{
+		ServiceName: req.ServiceName,
+		BaseURL:     req.BaseURL,
+		Auth:        req.Auth,
+		Request: restclient.RESTRequest{
+			Method:   restclient.GET,
+			Endpoint: statusURL,
+		},
+		Timeout: req.Timeout,
+	}
+
+	logger.Info("Request accepted, polling for completion",
+		"service", req.ServiceName,
+		"status_url", statusURL,
+		"poll_interval", pollInterval,
+		"max_polls", maxPolls)
+
+	for poll := 1; poll <= maxPolls; poll++ {
+		select {
+		case <-time.After(pollInterval):
+		case <-ctx.Done():
+			return resp, fmt.Errorf("polling cancelled after %d polls: %w", poll-1, ctx.Err())
+		}
+		activity.RecordHeartbeat(ctx, poll)
+
+		resp, err = a.InvokeRESTService(ctx, pollReq)
+		if err != nil {
+			return resp, err
+		}
+		if !resp.Success {
+			return resp, fmt.Errorf("status poll %d failed: %s", poll, resp.ErrorMessage)
+		}
+		if completePredicate(resp) {
+			logger.Info("Asynchronous operation complete",
+				"service", req.ServiceName,
+				"polls", poll)
+			return resp, nil
+		}
+	}
+
+	return resp, fmt.Errorf("%s operation not complete after %d polls", req.ServiceName, maxPolls)
+}
+
+// resolveLocation resolves a possibly relative Location header against the request URL.
+// The status resource is polled with the request's credentials, so a Location on another
+// scheme or host is rejected rather than leaking them to it.
+func resolveLocation(requestURL, location string) (string, error) {
+	base, err := url.Parse(requestURL)
+	if err != nil {
+		return "", fmt.Errorf("invalid request URL %s: %w", requestURL, err)
+	}
+	ref, err := url.Parse(location)
+	if err != nil {
+		return "", fmt.Errorf("invalid Location header %s: %w", location, err)
+	}
+	resolved := base.ResolveReference(ref)
+	if !strings.EqualFold(resolved.Scheme, base.Scheme) || !strings.EqualFold(resolved.Host, base.Host) {
+		return "", fmt.Errorf("Location %s is not on the same origin as %s; refusing to send credentials to it", location, requestURL)
+	}
+	return resolved.String(), nil
+}
+
+// downloadHeartbeatInterval is how often DownloadAndVerify heartbeats while receiving
+const downloadHeartbeatInterval = 5 * time.Second
+
+// DownloadAndVerify streams the response body to destPath while computing its SHA-256,
+// heartbeating as bytes are received. On a digest mismatch or any failure the partial
+// file is deleted. The client's 30s timeout does not apply; req.Timeout, when set, bounds
+// the whole download.
+func (a *RESTServiceActivities) DownloadAndVerify(ctx context.Context, req RESTServiceRequest, expectedSHA256 string, destPath string) error {
+	logger := activity.GetLogger(ctx)
+	logger.Info("Downloading artifact",
+		"service", req.ServiceName,
+		"endpoint", req.Request.Endpoint,
+		"dest", destPath)
+
+	client, err := a.getClient(req.BaseURL, req.Auth)
+	if err != nil {
+		return fmt.Errorf("failed to create REST client: %w", err)
+	}
+
+	if req.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, req.Timeout)
+		defer cancel()
+	}
+
+	// Stream the body rather than buffer it; only ctx bounds how long it may take
+	httpResp, err := client.OpenStream(ctx, req.Request)
+	if err != nil {
+		return fmt.Errorf("download request failed: %w", err)
+	}
+	defer httpResp.Body.Close()
+
+	if httpResp.StatusCode < 200 || httpResp.StatusCode >= 300 {
+		return fmt.Errorf("HTTP %d: download from %s failed: %s", httpResp.StatusCode, req.ServiceName, httpResp.Status)
+	}
+
+	file, err := os.Create(destPath)
+	if err != nil {
+		return fmt.Errorf("failed to create download file: %w", err)
+	}
+
+	hash := sha256.New()
+	body := &heartbeatReader{ctx: ctx, reader: httpResp.Body, interval: downloadHeartbeatInterval}
+	written, copyErr := io.Copy(io.MultiWriter(file, hash), body)
+	closeErr := file.Close()
+	if copyErr != nil || closeErr != nil {
+		os.Remove(destPath)
+		if copyErr != nil {
+			return fmt.Errorf("failed to write download file: %w", copyErr)
+		}
+		return fmt.Errorf("failed to write download file: %w", closeErr)
+	}
+
+	digest := hex.EncodeToString(hash.Sum(nil))
+	if !strings.EqualFold(digest, strings.TrimSpace(expectedSHA256)) {
+		os.Remove(destPath)
+		logger.Error("Checksum mismatch, removed download",
+			"service", req.ServiceName,
+			"expected", expectedSHA256,
+			"actual", digest)
+		return fmt.Errorf("checksum mismatch for %s: expected sha256 %s, got %s", destPath, expectedSHA256, digest)
+	}
+
+	logger.Info("Download verified",
+		"service", req.ServiceName,
+		"dest", destPath,
+		"bytes", written)
+
+	return nil
+}
+
+// BatchRESTCalls executes multiple REST calls in sequence
+func (a *RESTServiceActivities) BatchRESTCalls(ctx context.Context, requests []RESTServiceRequest) ([]*RESTServiceResponse, error) {
+	logger := activity.GetLogger(ctx)
+	logger.Info("Executing batch REST calls", "count", len(requests))
+
+	responses := make([]*RESTServiceResponse, len(requests))
+
+	for i, req := range requests {
+		// Stop issuing requests once the activity is cancelled or times out
+		if err := ctx.Err(); err != nil {
+			logger.Warn("Batch REST calls cancelled",
+				"completed", i,
+				"total", len(requests),
+				"error", err)
+			return responses[:i], err
+		}
+
+		logger.Info("Executing batch request",
+			"index", i+1,
+			"of", len(requests),
+			"service", req.ServiceName,
+			"endpoint", req.Request.Endpoint)
+
+		resp, err := a.InvokeRESTService(ctx, req)
+		if err != nil {
+			logger.Error("Batch request failed",
+				"index", i+1,
+				"service", req.ServiceName,
+				"error", err)
+			responses[i] = &RESTServiceResponse{
+				ServiceName:  req.ServiceName,
+				Success:      false,
+				ErrorMessage: err.Error(),
+			}
+		} else {
+			responses[i] = resp
+		}
+	}
+
+	// Count results
+	successful := 0
+	failed := 0
+	for _, resp := range responses {
+		if resp.Success {
+			successful++
+		} else {
+			failed++
+		}
+	}
+
+	logger.Info("Batch REST calls completed",
+		"total", len(requests),
+		"successful", successful,
+		"failed", failed)
+
+	return responses, nil
+}
+
+// IndexedResponse pairs a batch response with the index of its request
+type IndexedResponse struct {
+	Index    int                  `json:"index"`
+	Response *RESTServiceResponse `json:"response"`
+}
+
+// DefaultMaxConcurrentBatchCalls bounds how many requests BatchRESTCallsStream runs at once
+// when maxConcurrency is not positive
+const DefaultMaxConcurrentBatchCalls = 5
+
+// BatchRESTCallsStream executes REST calls concurrently, at most maxConcurrency at a time, and
+// emits each response as it completes. Responses arrive in completion order; the channel is
+// closed once all started requests have finished. When ctx ends no further requests are
+// started, so the channel may hold fewer responses than requests.
+// Channels cannot be returned from an activity, so call it from within one and drain the
+// channel before the activity returns.
+func BatchRESTCallsStream(ctx context.Context, a *RESTServiceActivities, requests []RESTServiceRequest, maxConcurrency int) (<-chan IndexedResponse, error) {
+	if len(requests) == 0 {
+		return nil, fmt.Errorf("no requests provided for batch")
+	}
+	if maxConcurrency <= 0 {
+		maxConcurrency = DefaultMaxConcurrentBatchCalls
+	}
+
+	logger := activity.GetLogger(ctx)
+	logger.Info("Streaming batch REST calls", "count", len(requests), "max_concurrency", maxConcurrency)
+
+	// Buffered for every response so workers never block on a slow consumer
+	results := make(chan IndexedResponse, len(requests))
+	semaphore := make(chan struct{}, maxConcurrency)
+
+	go func() {
+		var wg sync.WaitGroup
+		defer close(results)
+		defer wg.Wait()
+
+		for i, req := range requests {
+			select {
+			case semaphore <- struct{}{}:
+			case <-ctx.Done():
+			}
+			if err := ctx.Err(); err != nil {
+				logger.Warn("Batch REST calls stream cancelled",
+					"started", i,
+					"total", len(requests),
+					"error", err)
+				return
+			}
+
+			wg.Add(1)
+			go func(index int, req RESTServiceRequest) {
+				defer wg.Done()
+				defer func() { <-semaphore }()
+
+				resp, err := a.InvokeRESTService(ctx, req)
+				if err != nil {
+					logger.Error("Batch request failed",
+						"index", index+1,
+						"service", req.ServiceName,
+						"error", err)
+					resp = &RESTServiceResponse{
+						ServiceName:  req.ServiceName,
+						Success:      false,
+						ErrorMessage: err.Error(),
+					}
+				}
+
+				results <- IndexedResponse{Index: index, Response: resp}
+			}(i, req)
+		}
+	}()
+
+	return results, nil
+}
+
+// BatchRESTCallsWithSummary executes multiple REST calls in sequence and summarizes the outcome
+func (a *RESTServiceActivities) BatchRESTCallsWithSummary(ctx context.Context, requests []RESTServiceRequest) (*BatchSummary, error) {
+	responses, err := a.BatchRESTCalls(ctx, requests)
+	if err != nil {
+		return nil, err
+	}
+
+	return summarizeBatch(responses), nil
+}
+
+// summarizeBatch computes the batch outcome from per-request results
+func summarizeBatch(responses []*RESTServiceResponse) *BatchSummary {
+	summary := &BatchSummary{
+		Total:     len(responses),
+		Responses: responses,
+	}
+
+	for _, resp := range responses {
+		if resp.Success {
+			summary.Successful++
+		} else {
+			summary.Failed++
+		}
+	}
+
+	switch {
+	case summary.Failed == 0:
+		summary.Outcome = AllSucceeded
+	case summary.Successful == 0:
+		summary.Outcome = AllFailed
+	default:
+		summary.Outcome = PartialSuccess
+	}
+
+	return summary
+}
+
+// BatchRESTCallsFailFast executes multiple REST calls in sequence, stopping at the first failure.
+// The partial responses are returned alongside the error and attached to it as error details.
+func (a *RESTServiceActivities) BatchRESTCallsFailFast(ctx context.Context, requests []RESTServiceRequest) ([]*RESTServiceResponse, error) {
+	logger := activity.GetLogger(ctx)
+	logger.Info("Executing fail-fast batch REST calls", "count", len(requests))
+
+	responses := make([]*RESTServiceResponse, 0, len(requests))
+
+	for i, req := range requests {
+		logger.Info("Executing batch request",
+			"index", i+1,
+			"of", len(requests),
+			"service", req.ServiceName,
+			"endpoint", req.Request.Endpoint)
+
+		resp, err := a.InvokeRESTService(ctx, req)
+		if err != nil {
+			resp = &RESTServiceResponse{
+				ServiceName:  req.ServiceName,
+				Success:      false,
+				ErrorMessage: err.Error(),
+			}
+		}
+		responses = append(responses, resp)
+
+		if !resp.Success {
+			logger.Error("Batch request failed, aborting remaining requests",
+				"index", i+1,
+				"service", req.ServiceName,
+				"error", resp.ErrorMessage)
+			return responses, temporal.NewApplicationError(
+				fmt.Sprintf("batch request at index %d (%s) failed: %s", i, req.ServiceName, resp.ErrorMessage),
+				"BatchRequestFailed",
+				responses)
+		}
+	}
+
+	logger.Info("Batch REST calls completed",
+		"total", len(requests),
+		"successful", len(responses))
+
+	return responses, nil
+}
+
+// ValidateRESTResponse validates REST response against expected criteria
+func (a *RESTServiceActivities) ValidateRESTResponse(ctx context.Context, response *RESTServiceResponse, expectedStatusCode int, requiredFields []string) error {
+	logger := activity.GetLogger(ctx)
+
+	// Check status code
+	if expectedStatusCode > 0 && response.StatusCode != expectedStatusCode {
+		return fmt.Errorf("expected status code %d, got %d", expectedStatusCode, response.StatusCode)
+	}
+
+	// Check required fields in JSON response
+	if len(requiredFields) > 0 {
+		var jsonData map[string]interface{}
+		if err := json.Unmarshal([]byte(response.Body), &jsonData); err != nil {
+			return fmt.Errorf("failed to parse JSON response: %v", err)
+		}
+
+		for _, field := range requiredFields {
+			if _, exists := jsonData[field]; !exists {
+				return fmt.Errorf("required field '%s' not found in response", field)
+			}
+		}
+	}
+
+	logger.Info("REST response validation successful",
 		"service", response.ServiceName,
 		"status_code", response.StatusCode)
 
 	return nil
 }
 
+// ValidateContentType validates that the response media type matches the expected one, ignoring parameters such as charset
+func (a *RESTServiceActivities) ValidateContentType(ctx context.Context, response *RESTServiceResponse, expectedContentType string) error {
+	logger := activity.GetLogger(ctx)
+
+	expected := mediaType(expectedContentType)
+	actual := mediaType(response.ContentType)
+	if actual != expected {
+		return fmt.Errorf("expected content type %s, got %s", expected, response.ContentType)
+	}
+
+	logger.Info("REST response content type validation successful",
+		"service", response.ServiceName,
+		"content_type", response.ContentType)
+
+	return nil
+}
+
+// ValidateResponseHeaders validates response headers: an empty required value means the
+// header must exist, a non-empty value means it must equal the first header value exactly
+func (a *RESTServiceActivities) ValidateResponseHeaders(ctx context.Context, response *RESTServiceResponse, required map[string]string) error {
+	logger := activity.GetLogger(ctx)
+
+	// Canonicalize keys, since headers may have been stored or requested in any case
+	headers := make(http.Header, len(response.Headers))
+	for key, values := range response.Headers {
+		canonical := http.CanonicalHeaderKey(key)
+		headers[canonical] = append(headers[canonical], values...)
+	}
+
+	// Check in sorted order so the reported failure is deterministic
+	names := make([]string, 0, len(required))
+	for name := range required {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		values, exists := headers[http.CanonicalHeaderKey(name)]
+		if !exists || len(values) == 0 {
+			return fmt.Errorf("required header '%s' not found in response", name)
+		}
+		if expected := required[name]; expected != "" && values[0] != expected {
+			return fmt.Errorf("header '%s' expected '%s', got '%s'", name, expected, values[0])
+		}
+	}
+
+	logger.Info("REST response header validation successful",
+		"service", response.ServiceName,
+		"headers", len(required))
+
+	return nil
+}
+
+// ValidateBodyPattern validates that the response body matches a regular expression,
+// for non-JSON responses where only a substring or pattern needs confirming
+func (a *RESTServiceActivities) ValidateBodyPattern(ctx context.Context, response *RESTServiceResponse, pattern string) error {
+	logger := activity.GetLogger(ctx)
+
+	re, err := a.compilePattern(pattern)
+	if err != nil {
+		// Retrying cannot fix a malformed pattern
+		return temporal.NewNonRetryableApplicationError(
+			fmt.Sprintf("invalid body pattern %q: %v", pattern, err),
+			"InvalidArgument",
+			err)
+	}
+
+	if !re.MatchString(response.Body) {
+		return fmt.Errorf("response body does not match pattern %q", pattern)
+	}
+
+	logger.Info("REST response body pattern validation successful",
+		"service", response.ServiceName,
+		"pattern", pattern)
+
+	return nil
+}
+
+// compilePattern returns the compiled regular expression, compiling each pattern only once
+func (a *RESTServiceActivities) compilePattern(pattern string) (*regexp.Regexp, error) {
+	a.applyDefaults()
+
+	a.patternsMu.Lock()
+	defer a.patternsMu.Unlock()
+
+	if re, ok := a.patterns[pattern]; ok {
+		return re, nil
+	}
+
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, err
+	}
+	a.patterns[pattern] = re
+	return re, nil
+}
+
+// mediaType strips parameters such as charset from a content type
+func mediaType(contentType string) string {
+	if i := strings.Index(contentType, ";"); i >= 0 {
+		contentType = contentType[:i]
+	}
+	return strings.ToLower(strings.TrimSpace(contentType))
+}
+
+// isKnownBackoffFunc reports whether name is a supported backoff progression
+func isKnownBackoffFunc(name string) bool {
+	switch name {
+	case ConstantBackoff, LinearBackoff, ExponentialBackoff, FibonacciBackoff:
+		return true
+	default:
+		return false
+	}
+}
+
+// clampMaxAttempts bounds a configured attempt count to [1, maxAttemptsCap], warning when it changes
+func (a *RESTServiceActivities) clampMaxAttempts(logger log.Logger, serviceName string, maxAttempts int) int {
+	a.applyDefaults()
+
+	clamped := maxAttempts
+	switch {
+	case maxAttempts < 1:
+		clamped = 1
+	case maxAttempts > a.maxAttemptsCap:
+		clamped = a.maxAttemptsCap
+	}
+	if clamped != maxAttempts {
+		logger.Warn("Clamping retry max attempts",
+			"service", serviceName,
+			"max_attempts", maxAttempts,
+			"clamped_to", clamped)
+	}
+	return clamped
+}
+
+// newAttemptRecord summarizes the outcome of one attempt
+func newAttemptRecord(attempt int, resp *RESTServiceResponse, err error, duration time.Duration) AttemptRecord {
+	record := AttemptRecord{Attempt: attempt, Duration: duration}
+	if resp != nil {
+		record.StatusCode = resp.StatusCode
+		if !resp.Success {
+			record.Error = resp.ErrorMessage
+		}
+	}
+	if err != nil {
+		record.Error = err.Error()
+	}
+	return record
+}
+
+// logAttemptSummary logs one audit line for a retried call with its final outcome
+func logAttemptSummary(logger log.Logger, req RESTServiceRequest, result *RESTServiceResponse, err error, attempts []AttemptRecord, duration time.Duration) {
+	keyvals := []interface{}{
+		"service", req.ServiceName,
+		"method", req.Request.Method,
+		"endpoint", req.Request.Endpoint,
+		"success", err == nil && result != nil && result.Success,
+		"attempts", len(attempts),
+		"total_duration", duration,
+	}
+	if result != nil {
+		keyvals = append(keyvals, "status_code", result.StatusCode)
+	}
+	if err != nil {
+		keyvals = append(keyvals, "error", err)
+	}
+	logger.Info("REST service call summary", keyvals...)
+}
+
+// isRetryableError reports whether a transport error should be retried. Every error is
+// retried when no substrings are configured.
+func isRetryableError(err error, substrings []string) bool {
+	if len(substrings) == 0 {
+		return true
+	}
+	message := err.Error()
+	for _, substring := range substrings {
+		if strings.Contains(message, substring) {
+			return true
+		}
+	}
+	return false
+}
+
+// backoffDuration returns the wait after the given failed attempt (1-based), capped at MaxBackoff
+func backoffDuration(config *RetryConfig, attempt int) time.Duration {
+	var wait time.Duration
+	switch config.BackoffFunc {
+	case ConstantBackoff:
+		wait = config.InitialBackoff
+	case LinearBackoff:
+		wait = config.InitialBackoff * time.Duration(attempt)
+	case FibonacciBackoff:
+		prev, curr := 0, 1
+		for i := 1; i < attempt; i++ {
+			prev, curr = curr, prev+curr
+		}
+		wait = config.InitialBackoff * time.Duration(curr)
+	default:
+		wait = time.Duration(float64(config.InitialBackoff) * math.Pow(config.BackoffMultiplier, float64(attempt-1)))
+	}
+
+	if config.MaxBackoff > 0 && wait > config.MaxBackoff {
+		wait = config.MaxBackoff
+	}
+	return wait
+}
+
+// parseRetryAfter reads the Retry-After header as delay seconds or an HTTP date
+func parseRetryAfter(headers map[string][]string) (time.Duration, bool) {
+	value := http.Header(headers).Get("Retry-After")
+	if value == "" {
+		return 0, false
+	}
+
+	if seconds, err := strconv.Atoi(value); err == nil && seconds >= 0 {
+		return time.Duration(seconds) * time.Second, true
+	}
+
+	if date, err := http.ParseTime(value); err == nil {
+		delay := time.Until(date)
+		if delay < 0 {
+			delay = 0
+		}
+		return delay, true
+	}
+
+	return 0, false
+}
+
+// retryAfterWait adds random jitter of up to jitter*retryAfter on top of the
+// server's Retry-After so workers don't retry in lockstep. The wait is never
+// shorter than retryAfter; a positive maxWait keeps the jitter from pushing it
+// past maxWait. A nil rng uses the shared math/rand source.
+func retryAfterWait(retryAfter time.Duration, jitter float64, maxWait time.Duration, rng *rand.Rand) time.Duration {
+	if jitter <= 0 || retryAfter <= 0 {
+		return retryAfter
+	}
+
+	var r float64
+	if rng != nil {
+		r = rng.Float64()
+	} else {
+		r = rand.Float64()
+	}
+
+	wait := retryAfter + time.Duration(r*jitter*float64(retryAfter))
+	if maxWait > 0 && wait > maxWait {
+		wait = maxWait
+	}
+	if wait < retryAfter {
+		wait = retryAfter
+	}
+	return wait
+}
+
+// sanitizeSentRequest copies the sent request with credentials redacted
+func sanitizeSentRequest(httpReq *http.Request, body interface{}, auth restclient.AuthConfig) *SentRequest {
+	secretHeaders := map[string]bool{
+		"Authorization":       true,
+		"Proxy-Authorization": true,
+		"Cookie":              true,
+		"X-Api-Key":           true,
+	}
+	if auth.KeyHeader != "" {
+		secretHeaders[http.CanonicalHeaderKey(auth.KeyHeader)] = true
+	}
+	for name := range auth.ExtraAuthHeaders {
+		secretHeaders[http.CanonicalHeaderKey(name)] = true
+	}
+
+	headers := make(map[string][]string, len(httpReq.Header))
+	for key, values := range httpReq.Header {
+		if secretHeaders[http.CanonicalHeaderKey(key)] {
+			headers[key] = []string{redactedValue}
+			continue
+		}
+		headers[key] = append([]string(nil), values...)
+	}
+
+	secretParams := map[string]bool{
+		"api_key":       true,
+		"apikey":        true,
+		"access_token":  true,
+		"token":         true,
+		"password":      true,
+		"secret":        true,
+		"client_secret": true,
+	}
+	if auth.KeyQuery != "" {
+		secretParams[strings.ToLower(auth.KeyQuery)] = true
+	}
+
+	u := *httpReq.URL
+	query := u.Query()
+	for key := range query {
+		if secretParams[strings.ToLower(key)] {
+			query.Set(key, redactedValue)
+		}
+	}
+	u.RawQuery = query.Encode()
+
+	sent := &SentRequest{
+		Method:  httpReq.Method,
+		URL:     redactURL(&u),
+		Headers: headers,
+	}
+
+	switch b := body.(type) {
+	case nil:
+	case string:
+		sent.Body = b
+	default:
+		if bodyBytes, err := json.Marshal(b); err == nil {
+			sent.Body = string(bodyBytes)
+		}
+	}
+
+	return sent
+}
+
+// redactURL renders the URL with any userinfo password redacted
+func redactURL(u *url.URL) string {
+	if _, hasPassword := u.User.Password(); hasPassword {
+		redacted := *u
+		redacted.User = url.UserPassword(u.User.Username(), redactedValue)
+		return redacted.String()
+	}
+	return u.String()
+}
+
+// applyResponseTransform applies a registered transform to the response body
+func (a *RESTServiceActivities) applyResponseTransform(name string, result *RESTServiceResponse) error {
+	a.applyDefaults()
+
+	transform, exists := a.registry.transforms[name]
+	if !exists {
+		return fmt.Errorf("unknown response transform: %s", name)
+	}
+
+	body, err := transform(result.Body)
+	if err != nil {
+		return fmt.Errorf("response transform '%s' failed: %w", name, err)
+	}
+
+	result.Body = body
+	return nil
+}
+
+// extractDataField returns the "data" field of a JSON object body
+func extractDataField(body string) (string, error) {
+	var envelope map[string]json.RawMessage
+	if err := json.Unmarshal([]byte(body), &envelope); err != nil {
+		return "", fmt.Errorf("failed to parse JSON response: %v", err)
+	}
+
+	data, exists := envelope["data"]
+	if !exists {
+		return "", fmt.Errorf("required field 'data' not found in response")
+	}
+
+	return string(data), nil
+}
+
+// firstArrayElement returns the first element of a JSON array body
+func firstArrayElement(body string) (string, error) {
+	var elements []json.RawMessage
+	if err := json.Unmarshal([]byte(body), &elements); err != nil {
+		return "", fmt.Errorf("failed to parse JSON response: %v", err)
+	}
+
+	if len(elements) == 0 {
+		return "", fmt.Errorf("response array is empty")
+	}
+
+	return string(elements[0]), nil
+}
+
+// containsStatusCode reports whether statusCode is in codes
+func containsStatusCode(codes []int, statusCode int) bool {
+	for _, code := range codes {
+		if code == statusCode {
+			return true
+		}
+	}
+	return false
+}
+
 // isRetryableStatus checks if status code is retryable
 func (a *RESTServiceActivities) isRetryableStatus(statusCode int, retryableStatusCodes []int) bool {
 	for _, code := range retryableStatusCodes {
@@ -414,6 +1938,7 @@ func GetRESTActivityOptions() *activity.Options {
 				"InvalidArgument",
 				"PermissionDenied",
 				"Unauthenticated",
+				PermanentFailureErrorType,
 			},
 		},
 	}
@@ -433,6 +1958,7 @@ func GetLongRunningRESTActivityOptions() *activity.Options {
 				"InvalidArgument",
 				"PermissionDenied",
 				"Unauthenticated",
+				PermanentFailureErrorType,
 			},
 		},
 	}
@@ -453,16 +1979,20 @@ type HealthCheckRequest struct {
 	BaseURL     string                `json:"base_url"`
 	Auth        restclient.AuthConfig `json:"auth"`
 	Endpoint    string                `json:"endpoint,omitempty"` // Default: /health
-	Timeout     time.Duration         `json:"timeout,omitempty"`   // Default: 10s
+	Timeout     time.Duration         `json:"timeout,omitempty"`  // Default: 10s
+
+	// Optional JSON body check, e.g. field "status" must equal "ok"; dotted paths reach nested fields
+	ExpectedStatusField string `json:"expected_status_field,omitempty"`
+	ExpectedStatusValue string `json:"expected_status_value,omitempty"`
 }
 
 // HealthCheckResponse represents a health check response
 type HealthCheckResponse struct {
-	ServiceName string        `json:"service_name"`
-	IsHealthy   bool          `json:"is_healthy"`
-	StatusCode  int           `json:"status_code"`
-	Duration    time.Duration `json:"duration"`
-	ErrorMessage string       `json:"error_message,omitempty"`
+	ServiceName  string        `json:"service_name"`
+	IsHealthy    bool          `json:"is_healthy"`
+	StatusCode   int           `json:"status_code"`
+	Duration     time.Duration `json:"duration"`
+	ErrorMessage string        `json:"error_message,omitempty"`
 }
 
 // HealthCheck performs a health check on a REST service
@@ -515,6 +2045,16 @@ func (a *RESTServiceActivities) HealthCheck(ctx context.Context, req HealthCheck
 
 	if !healthResp.IsHealthy {
 		healthResp.ErrorMessage = resp.ErrorMessage
+	} else if req.ExpectedStatusField != "" {
+		actual, err := jsonFieldString(resp.Body, req.ExpectedStatusField)
+		if err != nil {
+			healthResp.IsHealthy = false
+			healthResp.ErrorMessage = err.Error()
+		} else if actual != req.ExpectedStatusValue {
+			healthResp.IsHealthy = false
+			healthResp.ErrorMessage = fmt.Sprintf("health field '%s' is '%s', expected '%s'",
+				req.ExpectedStatusField, actual, req.ExpectedStatusValue)
+		}
 	}
 
 	logger.Info("Health check completed",
@@ -524,4 +2064,191 @@ func (a *RESTServiceActivities) HealthCheck(ctx context.Context, req HealthCheck
 		"duration", healthResp.Duration)
 
 	return healthResp, nil
-}
\ No newline at end of file
+}
+
+// PingFailure classifies why a host could not be reached
+type PingFailure string
+
+const (
+	PingDNSFailure        PingFailure = "dns"
+	PingConnectionRefused PingFailure = "connection_refused"
+	PingTLSFailure        PingFailure = "tls"
+	PingTimeout           PingFailure = "timeout"
+	PingUnreachable       PingFailure = "unreachable" // Any other network error
+)
+
+// PingResult represents the outcome of a reachability check
+type PingResult struct {
+	Address      string        `json:"address"` // host:port that was dialed
+	Reachable    bool          `json:"reachable"`
+	Latency      time.Duration `json:"latency"` // Connection setup time, including the TLS handshake for https
+	Failure      PingFailure   `json:"failure,omitempty"`
+	ErrorMessage string        `json:"error_message,omitempty"`
+}
+
+// Ping checks that the host in baseURL accepts connections, completing a TLS handshake for
+// https URLs, without sending an HTTP request. Unlike HealthCheck it says nothing about the
+// application, only whether the host is reachable and how long connecting took. Unreachable
+// hosts are reported in the result; only an invalid baseURL returns an error.
+func (a *RESTServiceActivities) Ping(ctx context.Context, baseURL string, timeout time.Duration) (*PingResult, error) {
+	logger := activity.GetLogger(ctx)
+
+	u, err := url.Parse(baseURL)
+	if err != nil || u.Hostname() == "" || (u.Scheme != "http" && u.Scheme != "https") {
+		if err == nil {
+			err = fmt.Errorf("expected an http or https URL")
+		}
+		return nil, temporal.NewNonRetryableApplicationError(
+			fmt.Sprintf("invalid base URL %q: %v", baseURL, err),
+			"InvalidArgument",
+			err)
+	}
+
+	if timeout == 0 {
+		timeout = 10 * time.Second
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	port := u.Port()
+	if port == "" {
+		port = "80"
+		if u.Scheme == "https" {
+			port = "443"
+		}
+	}
+	result := &PingResult{Address: net.JoinHostPort(u.Hostname(), port)}
+
+	start := time.Now()
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, "tcp", result.Address)
+	if err == nil {
+		defer conn.Close()
+		if u.Scheme == "https" {
+			tlsConn := tls.Client(conn, &tls.Config{ServerName: u.Hostname()})
+			if err = tlsConn.HandshakeContext(ctx); err != nil && !isTimeout(err) {
+				result.Failure = PingTLSFailure
+			}
+		}
+	}
+	result.Latency = time.Since(start)
+
+	if err != nil {
+		if result.Failure == "" {
+			result.Failure = classifyDialError(err)
+		}
+		result.ErrorMessage = err.Error()
+		logger.Warn("Ping failed",
+			"address", result.Address,
+			"failure", result.Failure,
+			"error", err)
+		return result, nil
+	}
+
+	result.Reachable = true
+	logger.Info("Ping succeeded",
+		"address", result.Address,
+		"latency", result.Latency)
+	return result, nil
+}
+
+// classifyDialError maps a dial error to a PingFailure
+func classifyDialError(err error) PingFailure {
+	var dnsErr *net.DNSError
+	switch {
+	case errors.As(err, &dnsErr):
+		return PingDNSFailure
+	case errors.Is(err, syscall.ECONNREFUSED):
+		return PingConnectionRefused
+	case isTimeout(err):
+		return PingTimeout
+	default:
+		return PingUnreachable
+	}
+}
+
+// isTimeout reports whether err is a network timeout or an expired deadline
+func isTimeout(err error) bool {
+	var netErr net.Error
+	return errors.Is(err, context.DeadlineExceeded) || (errors.As(err, &netErr) && netErr.Timeout())
+}
+
+// DefaultMaxConcurrentHealthChecks bounds how many health checks BatchHealthCheck runs at
+// once when maxConcurrency is not positive
+const DefaultMaxConcurrentHealthChecks = 5
+
+// BatchHealthCheck checks multiple services concurrently, at most maxConcurrency at a time,
+// and returns results in input order. A maxConcurrency of 0 uses DefaultMaxConcurrentHealthChecks.
+// Unhealthy services are reported in their results; only context cancellation returns an error.
+func (a *RESTServiceActivities) BatchHealthCheck(ctx context.Context, reqs []HealthCheckRequest, maxConcurrency int) ([]*HealthCheckResponse, error) {
+	logger := activity.GetLogger(ctx)
+	if maxConcurrency <= 0 {
+		maxConcurrency = DefaultMaxConcurrentHealthChecks
+	}
+	logger.Info("Performing batch health check", "count", len(reqs), "max_concurrency", maxConcurrency)
+
+	results := make([]*HealthCheckResponse, len(reqs))
+	semaphore := make(chan struct{}, maxConcurrency)
+
+	var wg sync.WaitGroup
+	for i, req := range reqs {
+		select {
+		case semaphore <- struct{}{}:
+		case <-ctx.Done():
+			wg.Wait()
+			return nil, ctx.Err()
+		}
+
+		wg.Add(1)
+		go func(index int, req HealthCheckRequest) {
+			defer wg.Done()
+			defer func() { <-semaphore }()
+
+			// HealthCheck reports failures in the response rather than as errors
+			results[index], _ = a.HealthCheck(ctx, req)
+		}(i, req)
+	}
+	wg.Wait()
+
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	healthy := 0
+	for _, result := range results {
+		if result.IsHealthy {
+			healthy++
+		}
+	}
+
+	logger.Info("Batch health check completed",
+		"total", len(reqs),
+		"healthy", healthy,
+		"unhealthy", len(reqs)-healthy)
+
+	return results, nil
+}
+
+// jsonFieldString returns the value at a dotted path in a JSON object body as a string
+func jsonFieldString(body, path string) (string, error) {
+	var current interface{}
+	if err := json.Unmarshal([]byte(body), &current); err != nil {
+		return "", fmt.Errorf("failed to parse health response: %v", err)
+	}
+
+	for _, key := range strings.Split(path, ".") {
+		object, ok := current.(map[string]interface{})
+		if !ok {
+			return "", fmt.Errorf("health field '%s' not found", path)
+		}
+		current, ok = object[key]
+		if !ok {
+			return "", fmt.Errorf("health field '%s' not found", path)
+		}
+	}
+
+	if str, ok := current.(string); ok {
+		return str, nil
+	}
+	return fmt.Sprint(current), nil
+}
@@ -0,0 +1,148 @@
+package activities
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+// DefaultLatencyBuckets mirror Prometheus' default histogram buckets
+var DefaultLatencyBuckets = []time.Duration{
+	5 * time.Millisecond,
+	10 * time.Millisecond,
+	25 * time.Millisecond,
+	50 * time.Millisecond,
+	100 * time.Millisecond,
+	250 * time.Millisecond,
+	500 * time.Millisecond,
+	1 * time.Second,
+	2500 * time.Millisecond,
+	5 * time.Second,
+	10 * time.Second,
+}
+
+// Metrics aggregates REST call counters across all activity invocations in a worker
+type Metrics struct {
+	mu            sync.Mutex
+	totalRequests int64
+	errors        int64
+	retries       int64
+	statusCodes   map[int]int64
+
+	buckets      []time.Duration
+	bucketCounts []int64 // Non-cumulative; the last slot counts observations above every bucket
+	latencySum   time.Duration
+	latencyCount int64
+}
+
+// MetricsSnapshot is a point-in-time copy of Metrics for export
+type MetricsSnapshot struct {
+	TotalRequests int64            `json:"total_requests"`
+	Errors        int64            `json:"errors"` // Requests that failed without an HTTP response
+	Retries       int64            `json:"retries"`
+	StatusCodes   map[int]int64    `json:"status_codes"`
+	Latency       LatencyHistogram `json:"latency"`
+}
+
+// LatencyHistogram is a cumulative histogram in the Prometheus style
+type LatencyHistogram struct {
+	Buckets []HistogramBucket `json:"buckets"`
+	Count   int64             `json:"count"`
+	Sum     time.Duration     `json:"sum"`
+}
+
+// HistogramBucket counts observations less than or equal to UpperBound
+type HistogramBucket struct {
+	UpperBound time.Duration `json:"upper_bound"`
+	Count      int64         `json:"count"`
+}
+
+// NewMetrics creates a metrics registry with the given latency buckets, or DefaultLatencyBuckets when none are given
+func NewMetrics(buckets ...time.Duration) *Metrics {
+	if len(buckets) == 0 {
+		buckets = DefaultLatencyBuckets
+	}
+	sorted := append([]time.Duration(nil), buckets...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+
+	return &Metrics{
+		statusCodes:  make(map[int]int64),
+		buckets:      sorted,
+		bucketCounts: make([]int64, len(sorted)+1),
+	}
+}
+
+// recordResponse counts a request that received an HTTP response
+func (m *Metrics) recordResponse(statusCode int, latency time.Duration) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.totalRequests++
+	m.statusCodes[statusCode]++
+
+	i := sort.Search(len(m.buckets), func(i int) bool { return latency <= m.buckets[i] })
+	m.bucketCounts[i]++
+	m.latencySum += latency
+	m.latencyCount++
+}
+
+// recordError counts a request that failed without an HTTP response
+func (m *Metrics) recordError() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.totalRequests++
+	m.errors++
+}
+
+// recordRetry counts a retry attempt
+func (m *Metrics) recordRetry() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.retries++
+}
+
+// Snapshot returns a consistent copy of the current counters
+func (m *Metrics) Snapshot() MetricsSnapshot {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	statusCodes := make(map[int]int64, len(m.statusCodes))
+	for code, count := range m.statusCodes {
+		statusCodes[code] = count
+	}
+
+	buckets := make([]HistogramBucket, len(m.buckets))
+	var cumulative int64
+	for i, bound := range m.buckets {
+		cumulative += m.bucketCounts[i]
+		buckets[i] = HistogramBucket{UpperBound: bound, Count: cumulative}
+	}
+
+	return MetricsSnapshot{
+		TotalRequests: m.totalRequests,
+		Errors:        m.errors,
+		Retries:       m.retries,
+		StatusCodes:   statusCodes,
+		Latency: LatencyHistogram{
+			Buckets: buckets,
+			Count:   m.latencyCount,
+			Sum:     m.latencySum,
+		},
+	}
+}
+
+// Reset clears all counters
+func (m *Metrics) Reset() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.totalRequests = 0
+	m.errors = 0
+	m.retries = 0
+	m.statusCodes = make(map[int]int64)
+	m.bucketCounts = make([]int64, len(m.buckets)+1)
+	m.latencySum = 0
+	m.latencyCount = 0
+}
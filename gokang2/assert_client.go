@@ -0,0 +1,94 @@
+package restclient
+
+import (
+	"encoding/json"
+	"net/http"
+	"reflect"
+)
+
+// TestingT is the subset of testing.TB used by response assertions
+type TestingT interface {
+	Helper()
+	Errorf(format string, args ...interface{})
+}
+
+// ResponseAssertion provides chainable assertions on a REST response for tests
+type ResponseAssertion struct {
+	t    TestingT
+	resp *RESTResponse
+}
+
+// Assert starts a chain of assertions on the response
+func Assert(t TestingT, resp *RESTResponse) *ResponseAssertion {
+	t.Helper()
+	if resp == nil {
+		t.Errorf("expected a response, got nil")
+	}
+	return &ResponseAssertion{t: t, resp: resp}
+}
+
+// Status asserts the response status code
+func (a *ResponseAssertion) Status(expected int) *ResponseAssertion {
+	a.t.Helper()
+	if a.resp == nil {
+		return a
+	}
+
+	if a.resp.StatusCode != expected {
+		a.t.Errorf("expected status code %d, got %d (%s)", expected, a.resp.StatusCode, a.resp.URL)
+	}
+	return a
+}
+
+// HeaderEquals asserts a response header value
+func (a *ResponseAssertion) HeaderEquals(key, expected string) *ResponseAssertion {
+	a.t.Helper()
+	if a.resp == nil {
+		return a
+	}
+
+	if actual := http.Header(a.resp.Headers).Get(key); actual != expected {
+		a.t.Errorf("expected header %s to be %q, got %q", key, expected, actual)
+	}
+	return a
+}
+
+// JSONField asserts the JSON value at a dotted path (see RESTResponse.GetField)
+func (a *ResponseAssertion) JSONField(path string, expected interface{}) *ResponseAssertion {
+	a.t.Helper()
+	if a.resp == nil {
+		return a
+	}
+
+	actual, err := a.resp.GetField(path)
+	if err != nil {
+		a.t.Errorf("expected JSON field '%s': %v", path, err)
+		return a
+	}
+
+	// Normalize expected value to its JSON form so 1 matches float64(1)
+	normalized, err := normalizeJSONValue(expected)
+	if err != nil {
+		a.t.Errorf("failed to compare JSON field '%s': %v", path, err)
+		return a
+	}
+
+	if !reflect.DeepEqual(normalized, actual) {
+		a.t.Errorf("expected JSON field '%s' to be %v, got %v", path, expected, actual)
+	}
+	return a
+}
+
+// normalizeJSONValue round-trips a value through JSON encoding
+func normalizeJSONValue(v interface{}) (interface{}, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+
+	var normalized interface{}
+	if err := json.Unmarshal(data, &normalized); err != nil {
+		return nil, err
+	}
+	return normalized, nil
+}
@@ -0,0 +1,87 @@
+package restclient
+
+import (
+	"context"
+	"time"
+)
+
+// RequestBuilder assembles a RESTRequest through chained calls and executes it with the client
+type RequestBuilder struct {
+	client *RESTClient
+	req    RESTRequest
+}
+
+// NewRequest starts building a request for the given method and endpoint
+func (c *RESTClient) NewRequest(method RESTMethod, endpoint string) *RequestBuilder {
+	return &RequestBuilder{
+		client: c,
+		req: RESTRequest{
+			Method:   method,
+			Endpoint: endpoint,
+		},
+	}
+}
+
+// Query sets a query parameter
+func (b *RequestBuilder) Query(key, value string) *RequestBuilder {
+	if b.req.QueryParams == nil {
+		b.req.QueryParams = make(map[string]string)
+	}
+	b.req.QueryParams[key] = value
+	return b
+}
+
+// Header sets a request header, overriding the client's default of the same name
+func (b *RequestBuilder) Header(key, value string) *RequestBuilder {
+	if b.req.Headers == nil {
+		b.req.Headers = make(map[string]string)
+	}
+	b.req.Headers[key] = value
+	return b
+}
+
+// Body sets the request body, encoded according to the Content-Type header
+func (b *RequestBuilder) Body(body interface{}) *RequestBuilder {
+	b.req.Body = body
+	return b
+}
+
+// JSONBody sets the request body and marks it as JSON
+func (b *RequestBuilder) JSONBody(body interface{}) *RequestBuilder {
+	b.req.Body = body
+	return b.Header("Content-Type", string(JSONContentType))
+}
+
+// Accept sets the content type to negotiate for the response
+func (b *RequestBuilder) Accept(contentType ContentType) *RequestBuilder {
+	b.req.Accept = contentType
+	return b
+}
+
+// BaseURL targets a different host than the client's base URL
+func (b *RequestBuilder) BaseURL(baseURL string) *RequestBuilder {
+	b.req.BaseURL = baseURL
+	return b
+}
+
+// Timeout sets the per-request timeout
+func (b *RequestBuilder) Timeout(timeout time.Duration) *RequestBuilder {
+	b.req.Timeout = timeout
+	return b
+}
+
+// Priority sets the request's place in the client's rate limiter queue
+func (b *RequestBuilder) Priority(priority Priority) *RequestBuilder {
+	b.req.Priority = priority
+	return b
+}
+
+// Build returns the assembled request
+func (b *RequestBuilder) Build() RESTRequest {
+	return b.req
+}
+
+// Do executes the assembled request
+func (b *RequestBuilder) Do(ctx context.Context) (*RESTResponse, error) {
+	return b.client.Execute(ctx, b.req)
+}
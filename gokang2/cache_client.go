@@ -0,0 +1,110 @@
+package restclient
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// TypedCache caches decoded GET responses so repeated reads of reference data
+// skip both the request and the unmarshaling until the entry expires
+type TypedCache[T any] struct {
+	client *RESTClient
+	ttl    time.Duration
+
+	mu      sync.Mutex
+	entries map[string]*typedCacheEntry[T]
+}
+
+// typedCacheEntry holds a decoded value with its validator and expiry
+type typedCacheEntry[T any] struct {
+	value     T
+	etag      string
+	expiresAt time.Time
+}
+
+// NewTypedCache creates a typed cache for GET responses that live for ttl
+func NewTypedCache[T any](client *RESTClient, ttl time.Duration) *TypedCache[T] {
+	return &TypedCache[T]{
+		client:  client,
+		ttl:     ttl,
+		entries: make(map[string]*typedCacheEntry[T]),
+	}
+}
+
+// Get returns the cached value for the endpoint, fetching and decoding it when missing or expired.
+// Expired entries with an ETag are revalidated; a 304 reuses the cached value without decoding.
+func (c *TypedCache[T]) Get(ctx context.Context, endpoint string, queryParams map[string]string) (T, error) {
+	key := c.client.buildURL("", endpoint, queryParams, nil, nil)
+
+	c.mu.Lock()
+	entry, cached := c.entries[key]
+	c.mu.Unlock()
+
+	if cached && time.Now().Before(entry.expiresAt) {
+		return entry.value, nil
+	}
+
+	req := RESTRequest{
+		Method:      GET,
+		Endpoint:    endpoint,
+		QueryParams: queryParams,
+	}
+	if cached && entry.etag != "" {
+		req.Headers = map[string]string{"If-None-Match": entry.etag}
+	}
+
+	resp, err := c.client.Execute(ctx, req)
+	if err != nil {
+		var zero T
+		return zero, err
+	}
+
+	// Not modified: extend the existing entry
+	if cached && resp.StatusCode == http.StatusNotModified {
+		c.store(key, entry.value, entry.etag)
+		return entry.value, nil
+	}
+
+	if !resp.IsSuccess() {
+		var zero T
+		return zero, fmt.Errorf("HTTP %d: failed to fetch %s", resp.StatusCode, resp.URL)
+	}
+
+	value, err := DecodeJSON[T](resp)
+	if err != nil {
+		return value, err
+	}
+
+	c.store(key, value, http.Header(resp.Headers).Get("ETag"))
+	return value, nil
+}
+
+// Invalidate removes the cached entry for the endpoint
+func (c *TypedCache[T]) Invalidate(endpoint string, queryParams map[string]string) {
+	key := c.client.buildURL("", endpoint, queryParams, nil, nil)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.entries, key)
+}
+
+// Clear removes all cached entries
+func (c *TypedCache[T]) Clear() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.entries = make(map[string]*typedCacheEntry[T])
+}
+
+// store saves a decoded value with a fresh expiry
+func (c *TypedCache[T]) store(key string, value T, etag string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.entries[key] = &typedCacheEntry[T]{
+		value:     value,
+		etag:      etag,
+		expiresAt: time.Now().Add(c.ttl),
+	}
+}
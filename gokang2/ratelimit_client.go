@@ -0,0 +1,136 @@
+package restclient
+
+import (
+	"context"
+	"math"
+	"sync"
+	"time"
+)
+
+// Priority orders requests waiting on the client's rate limiter
+type Priority string
+
+const (
+	PriorityNormal Priority = "normal" // Default for requests without a priority
+	PriorityHigh   Priority = "high"   // Served before any queued normal request, e.g. health checks
+)
+
+// RateLimit throttles requests with a token bucket. Tokens refill continuously rather
+// than once per second, so saturated requests are spaced 1/RequestsPerSecond apart
+// instead of bursting at second boundaries.
+type RateLimit struct {
+	RequestsPerSecond float64 // Sustained rate; zero or negative disables limiting
+	Burst             int     // Requests allowed back-to-back when idle. Default: 1
+}
+
+// WithRateLimit throttles every HTTP request the client sends, including retries.
+// Queued requests with PriorityHigh acquire tokens before queued normal requests;
+// within a priority, requests are served in arrival order.
+func (c *RESTClient) WithRateLimit(limit RateLimit) *RESTClient {
+	if limit.RequestsPerSecond <= 0 {
+		c.limiter = nil
+		return c
+	}
+	c.limiter = newRateLimiter(limit)
+	return c
+}
+
+// rateLimiter is a token bucket with a two-level FIFO wait queue
+type rateLimiter struct {
+	rate  float64 // Tokens added per second
+	burst float64
+
+	mu     sync.Mutex
+	tokens float64
+	last   time.Time
+	high   []chan struct{}
+	normal []chan struct{}
+	timer  *time.Timer
+}
+
+// newRateLimiter creates a limiter that starts with a full bucket
+func newRateLimiter(limit RateLimit) *rateLimiter {
+	burst := float64(limit.Burst)
+	if burst < 1 {
+		burst = 1
+	}
+	return &rateLimiter{
+		rate:   limit.RequestsPerSecond,
+		burst:  burst,
+		tokens: burst,
+		last:   time.Now(),
+	}
+}
+
+// wait blocks until a token is granted to the caller or ctx is done
+func (l *rateLimiter) wait(ctx context.Context, priority Priority) error {
+	ready := make(chan struct{})
+
+	l.mu.Lock()
+	if priority == PriorityHigh {
+		l.high = append(l.high, ready)
+	} else {
+		l.normal = append(l.normal, ready)
+	}
+	l.dispatch()
+	l.mu.Unlock()
+
+	select {
+	case <-ready:
+		return nil
+	case <-ctx.Done():
+		l.mu.Lock()
+		defer l.mu.Unlock()
+		if !l.remove(ready) {
+			// The token was granted as ctx ended; pass it on to the next waiter
+			l.tokens++
+			l.dispatch()
+		}
+		return ctx.Err()
+	}
+}
+
+// dispatch grants available tokens to queued waiters, high priority first, and arms
+// a timer for the next token while any remain queued. Callers hold mu.
+func (l *rateLimiter) dispatch() {
+	now := time.Now()
+	l.tokens = math.Min(l.burst, l.tokens+now.Sub(l.last).Seconds()*l.rate)
+	l.last = now
+
+	for l.tokens >= 1 {
+		var ready chan struct{}
+		switch {
+		case len(l.high) > 0:
+			ready, l.high = l.high[0], l.high[1:]
+		case len(l.normal) > 0:
+			ready, l.normal = l.normal[0], l.normal[1:]
+		default:
+			return
+		}
+		l.tokens--
+		close(ready)
+	}
+
+	if len(l.high)+len(l.normal) > 0 && l.timer == nil {
+		next := time.Duration((1 - l.tokens) / l.rate * float64(time.Second))
+		l.timer = time.AfterFunc(next, func() {
+			l.mu.Lock()
+			defer l.mu.Unlock()
+			l.timer = nil
+			l.dispatch()
+		})
+	}
+}
+
+// remove drops a waiter from the queue, reporting false if it was already granted. Callers hold mu.
+func (l *rateLimiter) remove(ready chan struct{}) bool {
+	for _, queue := range []*[]chan struct{}{&l.high, &l.normal} {
+		for i, waiter := range *queue {
+			if waiter == ready {
+				*queue = append((*queue)[:i:i], (*queue)[i+1:]...)
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,218 @@
+package restclient
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+)
+
+// fixture is a recorded request and response, stored as one JSON file per request
+type fixture struct {
+	Method     string      `json:"method"`
+	URL        string      `json:"url"` // Credentials are redacted
+	BodySHA256 string      `json:"body_sha256"`
+	StatusCode int         `json:"status_code"`
+	Status     string      `json:"status"`
+	Headers    http.Header `json:"headers"`
+	Body       []byte      `json:"body"`
+}
+
+// RecordTo saves every request the client sends, with its response, as a fixture in dir
+// for later use with ReplayFrom. Fixtures are keyed by method, URL and a hash of the request
+// body; repeating a request overwrites its fixture. Configured credentials are redacted from
+// recorded URLs and request headers are not stored. Transport options such as WithLocalAddr
+// still apply when set after RecordTo.
+func (c *RESTClient) RecordTo(dir string) (*RESTClient, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create fixture directory: %w", err)
+	}
+
+	c.wrapTransport(func(base http.RoundTripper) http.RoundTripper {
+		return &recordingTransport{base: base, dir: dir, redact: c.redactSecrets}
+	})
+	return c, nil
+}
+
+// ReplayFrom serves every request from fixtures in dir written by RecordTo, without
+// contacting the server. A request with no matching fixture fails.
+func (c *RESTClient) ReplayFrom(dir string) (*RESTClient, error) {
+	info, err := os.Stat(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open fixture directory: %w", err)
+	}
+	if !info.IsDir() {
+		return nil, fmt.Errorf("fixture path is not a directory: %s", dir)
+	}
+
+	c.wrapTransport(func(base http.RoundTripper) http.RoundTripper {
+		return &replayTransport{base: base, dir: dir, redact: c.redactSecrets}
+	})
+	return c, nil
+}
+
+// wrapTransport installs wrap around the outermost transport of both plain and OAuth2
+// requests, so OAuth2 token requests are neither recorded nor needed during replay
+func (c *RESTClient) wrapTransport(wrap func(base http.RoundTripper) http.RoundTripper) {
+	c.httpClient.Transport = wrap(roundTripperOrDefault(c.httpClient.Transport))
+	if c.oauth2Client != nil {
+		c.oauth2Client.Transport = wrap(roundTripperOrDefault(c.oauth2Client.Transport))
+	}
+}
+
+// wrappedTransport is a transport installed by wrapTransport around the client's own
+// transport, which transport options reach through baseTransport
+type wrappedTransport interface {
+	http.RoundTripper
+	baseTransport() *http.RoundTripper
+}
+
+// transportSlot returns where the transport beneath any wrappedTransport layers in *rt is
+// stored, so it can be inspected or replaced without dropping the wrappers
+func transportSlot(rt *http.RoundTripper) *http.RoundTripper {
+	for {
+		wrapped, ok := (*rt).(wrappedTransport)
+		if !ok {
+			return rt
+		}
+		rt = wrapped.baseTransport()
+	}
+}
+
+// roundTripperOrDefault returns rt, or http.DefaultTransport when rt is nil
+func roundTripperOrDefault(rt http.RoundTripper) http.RoundTripper {
+	if rt == nil {
+		return http.DefaultTransport
+	}
+	return rt
+}
+
+// recordingTransport forwards requests to base and saves each exchange as a fixture
+type recordingTransport struct {
+	base   http.RoundTripper
+	dir    string
+	redact func(string) string
+}
+
+func (t *recordingTransport) baseTransport() *http.RoundTripper { return &t.base }
+
+// CloseIdleConnections lets http.Client.CloseIdleConnections reach the wrapped transport
+func (t *recordingTransport) CloseIdleConnections() {
+	if closer, ok := t.base.(interface{ CloseIdleConnections() }); ok {
+		closer.CloseIdleConnections()
+	}
+}
+
+func (t *recordingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	req, body, err := readRequestBody(req)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := t.base.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+
+	respBody, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response for recording: %w", err)
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(respBody))
+
+	recorded := fixture{
+		Method:     req.Method,
+		URL:        t.redact(req.URL.String()),
+		BodySHA256: sha256Hex(body),
+		StatusCode: resp.StatusCode,
+		Status:     resp.Status,
+		Headers:    resp.Header,
+		Body:       respBody,
+	}
+	data, err := json.MarshalIndent(recorded, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode fixture: %w", err)
+	}
+	if err := os.WriteFile(filepath.Join(t.dir, fixtureName(recorded)), data, 0o644); err != nil {
+		return nil, fmt.Errorf("failed to write fixture: %w", err)
+	}
+	return resp, nil
+}
+
+// replayTransport answers requests from recorded fixtures. base is the transport it
+// replaced, kept so transport options still find it, but never used to send.
+type replayTransport struct {
+	base   http.RoundTripper
+	dir    string
+	redact func(string) string
+}
+
+func (t *replayTransport) baseTransport() *http.RoundTripper { return &t.base }
+
+func (t *replayTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	req, body, err := readRequestBody(req)
+	if err != nil {
+		return nil, err
+	}
+
+	key := fixture{Method: req.Method, URL: t.redact(req.URL.String()), BodySHA256: sha256Hex(body)}
+	data, err := os.ReadFile(filepath.Join(t.dir, fixtureName(key)))
+	if os.IsNotExist(err) {
+		return nil, fmt.Errorf("no recorded fixture for %s %s", key.Method, key.URL)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read fixture: %w", err)
+	}
+
+	var recorded fixture
+	if err := json.Unmarshal(data, &recorded); err != nil {
+		return nil, fmt.Errorf("failed to decode fixture: %w", err)
+	}
+
+	return &http.Response{
+		Status:        recorded.Status,
+		StatusCode:    recorded.StatusCode,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        recorded.Headers,
+		Body:          io.NopCloser(bytes.NewReader(recorded.Body)),
+		ContentLength: int64(len(recorded.Body)),
+		Request:       req,
+	}, nil
+}
+
+// readRequestBody reads the request body, returning a copy of the request whose body can
+// still be sent
+func readRequestBody(req *http.Request) (*http.Request, []byte, error) {
+	if req.Body == nil || req.Body == http.NoBody {
+		return req, nil, nil
+	}
+
+	body, err := io.ReadAll(req.Body)
+	req.Body.Close()
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to read request body: %w", err)
+	}
+
+	req = req.Clone(req.Context())
+	req.Body = io.NopCloser(bytes.NewReader(body))
+	return req, body, nil
+}
+
+// fixtureName derives the fixture file name from the request's method, URL and body hash
+func fixtureName(f fixture) string {
+	return sha256Hex([]byte(f.Method+" "+f.URL+" "+f.BodySHA256)) + ".json"
+}
+
+// sha256Hex returns the hex-encoded SHA-256 digest of data
+func sha256Hex(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
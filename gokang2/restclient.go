@@ -1,18 +1,36 @@
 package restclient
 
 import (
+	"bufio"
 	"bytes"
+	"compress/gzip"
 	"context"
+	"crypto/rand"
+	"crypto/tls"
 	"encoding/json"
+	"encoding/xml"
+	"errors"
 	"fmt"
 	"io"
+	"log/slog"
+	"net"
 	"net/http"
+	"net/http/httputil"
 	"net/url"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
 	"strings"
+	"sync"
 	"time"
+	"unicode/utf8"
 
+	"github.com/go-playground/validator/v10"
+	"go.opentelemetry.io/otel/propagation"
 	"golang.org/x/oauth2"
 	"golang.org/x/oauth2/clientcredentials"
+	"google.golang.org/protobuf/proto"
 )
 
 // RESTMethod represents HTTP methods for REST operations
@@ -38,6 +56,42 @@ const (
 	APIKeyAuth AuthType = "apikey"
 )
 
+// OAuth2Grant selects how an OAuth2 client obtains access tokens
+type OAuth2Grant string
+
+const (
+	OAuth2ClientCredentials OAuth2Grant = "client_credentials" // Default
+	OAuth2Password          OAuth2Grant = "password"           // Resource owner Username/Password
+	OAuth2RefreshToken      OAuth2Grant = "refresh_token"      // Long-lived RefreshToken
+)
+
+// ResponseFormat represents the response type a client expects
+type ResponseFormat string
+
+const (
+	FormatJSON ResponseFormat = "json"
+	FormatXML  ResponseFormat = "xml"
+)
+
+// ArrayEncoding controls how multi-valued query parameters are rendered
+type ArrayEncoding string
+
+const (
+	ArrayRepeat   ArrayEncoding = "repeat"   // a=1&a=2
+	ArrayBrackets ArrayEncoding = "brackets" // a[]=1&a[]=2
+	ArrayComma    ArrayEncoding = "comma"    // a=1,2
+)
+
+// ContentType represents common media types for request bodies and Accept negotiation
+type ContentType string
+
+const (
+	JSONContentType ContentType = "application/json"
+	XMLContentType  ContentType = "application/xml"
+	FormContentType ContentType = "application/x-www-form-urlencoded"
+	TextContentType ContentType = "text/plain"
+)
+
 // Authentication configuration
 type AuthConfig struct {
 	Type AuthType `json:"type"`
@@ -46,32 +100,78 @@ type AuthConfig struct {
 	Username string `json:"username,omitempty"`
 	Password string `json:"password,omitempty"`
 
+	// CredentialProvider, when set, supplies Basic credentials per request in place of
+	// Username/Password, e.g. rotating secrets read from a vault
+	CredentialProvider func(ctx context.Context) (username, password string, err error) `json:"-"`
+
 	// Bearer Token
 	Token string `json:"token,omitempty"`
 
-	// OAuth2 Configuration
-	ClientID     string   `json:"client_id,omitempty"`
-	ClientSecret string   `json:"client_secret,omitempty"`
-	TokenURL     string   `json:"token_url,omitempty"`
-	Scopes       []string `json:"scopes,omitempty"`
+	// TokenProvider, when set, supplies the bearer token per request in place of Token.
+	// Caching tokens until they expire is the provider's responsibility.
+	TokenProvider func(ctx context.Context) (string, error) `json:"-"`
+
+	// OAuth2 Configuration. The password grant uses Username/Password as the resource
+	// owner's credentials; ClientSecret is optional for password and refresh_token grants.
+	ClientID     string      `json:"client_id,omitempty"`
+	ClientSecret string      `json:"client_secret,omitempty"`
+	TokenURL     string      `json:"token_url,omitempty"`
+	Scopes       []string    `json:"scopes,omitempty"`
+	OAuth2Grant  OAuth2Grant `json:"oauth2_grant,omitempty"` // Default: client_credentials
+	RefreshToken string      `json:"refresh_token,omitempty"`
 
 	// API Key Configuration
 	APIKey    string `json:"api_key,omitempty"`
 	KeyHeader string `json:"key_header,omitempty"` // Default: "X-API-Key"
 	KeyQuery  string `json:"key_query,omitempty"`  // Alternative: send as query param
+
+	// ExtraAuthHeaders are sent with every request whatever the auth type, e.g. an
+	// X-API-Secret alongside the API key. Their values are redacted like other credentials.
+	ExtraAuthHeaders map[string]string `json:"extra_auth_headers,omitempty"`
 }
 
-// REST request configuration
+// REST request configuration.
+// BaseURL takes precedence over the client's base URL, so a single client
+// can target multiple hosts; leave it empty to use the client's base URL.
 type RESTRequest struct {
 	BaseURL     string            `json:"base_url"`
 	Endpoint    string            `json:"endpoint"`
 	Method      RESTMethod        `json:"method"`
 	Headers     map[string]string `json:"headers,omitempty"`
 	QueryParams map[string]string `json:"query_params,omitempty"`
-	Body        interface{}       `json:"body,omitempty"`
-	Timeout     time.Duration     `json:"timeout,omitempty"`
+
+	// QueryParamsMulti holds multi-valued query parameters, rendered per the client's ArrayEncoding
+	QueryParamsMulti map[string][]string `json:"query_params_multi,omitempty"`
+
+	// RawQuery passes prebuilt url.Values through unchanged. It is merged with QueryParams and
+	// QueryParamsMulti; on a conflicting key every RawQuery value replaces the other's values.
+	RawQuery url.Values    `json:"raw_query,omitempty"`
+	Body     interface{}   `json:"body,omitempty"` // An io.Reader body is streamed unmodified
+	Timeout  time.Duration `json:"timeout,omitempty"`
+
+	// Accept sets the Accept header independently of the body's Content-Type,
+	// e.g. to request XML while sending JSON. Empty keeps the client default.
+	Accept ContentType `json:"accept,omitempty"`
+
+	// Validate struct bodies using `validate` tags before sending
+	ValidateRequestBody bool `json:"validate_request_body,omitempty"`
+
+	// Gzip bodies larger than compressionThreshold
+	CompressBody bool `json:"compress_body,omitempty"`
+
+	// Detect JSON or XML from the body when the response has no Content-Type
+	SniffContentType bool `json:"sniff_content_type,omitempty"`
+
+	// Priority orders the request in the client's rate limiter queue. Default: PriorityNormal
+	Priority Priority `json:"priority,omitempty"`
 }
 
+// compressionThreshold is the minimum body size in bytes worth compressing
+const compressionThreshold = 1024
+
+// bodyValidator validates request bodies using `validate` struct tags
+var bodyValidator = validator.New()
+
 // REST response
 type RESTResponse struct {
 	StatusCode    int                 `json:"status_code"`
@@ -82,28 +182,105 @@ type RESTResponse struct {
 	ContentLength int64               `json:"content_length"`
 	Duration      time.Duration       `json:"duration"`
 	URL           string              `json:"url"`
+
+	// FinalURL is the URL the response came from after any redirects were followed
+	FinalURL string `json:"final_url,omitempty"`
+
+	// RequestID is the X-Request-ID header the request was sent with, if any
+	RequestID string `json:"request_id,omitempty"`
+
+	// ServerTiming is the raw Server-Timing header reported by the server, if any
+	ServerTiming string `json:"server_timing,omitempty"`
+
+	// Request is the HTTP request as it was sent, after headers and authentication were applied
+	Request *http.Request `json:"-"`
+
+	// RawRequest and RawResponse are the HTTP/1.1 wire form of the exchange, set when the
+	// client's CaptureWire is enabled. RawResponse holds the body as received, before any
+	// gzip decoding by the client.
+	RawRequest  []byte `json:"raw_request,omitempty"`
+	RawResponse []byte `json:"raw_response,omitempty"`
+
+	format             ResponseFormat
+	successPredicate   func(*RESTResponse) bool
+	failureStatusCodes []int
+	envelopeKey        string
 }
 
-// REST client with authentication support
+// REST client with authentication support.
+// A RESTClient is safe for concurrent use by multiple goroutines. The base URL, default
+// headers and expected response format may be changed while requests are in flight; other
+// options should be configured before the client is shared.
 type RESTClient struct {
 	httpClient   *http.Client
 	auth         AuthConfig
 	oauth2Client *http.Client
-	baseURL      string
+	oauth2Source *refreshableTokenSource
+	basePath     string
+
+	// mu guards baseURL, defaultHeaders and responseFormat
+	mu             sync.RWMutex
+	baseURL        string
 	defaultHeaders map[string]string
+	responseFormat ResponseFormat
+
+	disableKeepAlives bool
+	dialer            *net.Dialer
+	successPredicate  func(*RESTResponse) bool
+	statusHandlers    map[int]func(*RESTResponse) error
+	limiter           *rateLimiter
+
+	// Logger, when set, receives structured request/response logs. Credentials are never logged.
+	Logger *slog.Logger
+
+	// EnvelopeKey is the field UnmarshalData reads payloads from. Default: "data"
+	EnvelopeKey string
+
+	// ArrayEncoding controls how RESTRequest.QueryParamsMulti is rendered. Default: ArrayRepeat
+	ArrayEncoding ArrayEncoding
+
+	// FailureStatusCodes are treated as failures by IsSuccess even within 2xx, e.g. 206 Partial Content
+	FailureStatusCodes []int
+
+	// GenerateRequestID sets a random UUID X-Request-ID header on requests that don't already carry one
+	GenerateRequestID bool
+
+	// MaxLoggedBodyBytes bounds bodies embedded in error messages, such as OAuth2 token
+	// endpoint failures. Default: DefaultMaxLoggedBodyBytes
+	MaxLoggedBodyBytes int
+
+	// CaptureWire records the raw request and response bytes in RESTResponse.RawRequest and
+	// RawResponse for auditing. Captures include credentials sent in headers, except OAuth2
+	// tokens which the transport adds after capture, and buffer streamed bodies in memory.
+	CaptureWire bool
+
+	// RequestGzipAcceptEncoding sends Accept-Encoding: gzip on requests that don't set one. Go's
+	// transport only decompresses responses to the header it adds itself, which custom transports
+	// with DisableCompression never do, so gzip responses to an explicit request are decoded here.
+	RequestGzipAcceptEncoding bool
+
+	// CanonicalJSON marshals JSON bodies with sorted object keys and no insignificant
+	// whitespace, so identical payloads produce identical bytes for signing and cache keys
+	CanonicalJSON bool
+
+	propagator propagation.TextMapPropagator
 }
 
+// DefaultEnvelopeKey is the field APIs commonly wrap payloads in, e.g. {"data": ..., "meta": ...}
+const DefaultEnvelopeKey = "data"
+
 // NewRESTClient creates a new REST client
 func NewRESTClient(baseURL string, auth AuthConfig) (*RESTClient, error) {
 	client := &RESTClient{
 		httpClient: &http.Client{
 			Timeout: 30 * time.Second,
 		},
-		auth:    auth,
-		baseURL: strings.TrimSuffix(baseURL, "/"),
+		auth:           auth,
+		baseURL:        strings.TrimSuffix(baseURL, "/"),
+		responseFormat: FormatJSON,
 		defaultHeaders: map[string]string{
-			"Content-Type": "application/json",
-			"Accept":       "application/json",
+			"Content-Type": string(JSONContentType),
+			"Accept":       string(JSONContentType),
 			"User-Agent":   "RESTClient/1.0",
 		},
 	}
@@ -118,37 +295,823 @@ func NewRESTClient(baseURL string, auth AuthConfig) (*RESTClient, error) {
 	return client, nil
 }
 
+// Options customizes the default headers installed by NewRESTClientWithOptions
+type Options struct {
+	// UserAgent replaces the default "RESTClient/1.0" User-Agent
+	UserAgent string
+	// DisableDefaultHeaders starts from an empty default header set, so no
+	// Content-Type, Accept or User-Agent is sent unless explicitly added
+	DisableDefaultHeaders bool
+	// BasePath is prepended to every endpoint resolved against the client's base URL,
+	// e.g. "/api/v2". See WithBasePath.
+	BasePath string
+	// RateLimit throttles outbound requests. See WithRateLimit.
+	RateLimit *RateLimit
+	// ForceHTTP2 and DisableHTTP2 select the HTTP version; setting both is an error.
+	// See RESTClient.ForceHTTP2 and RESTClient.DisableHTTP2.
+	ForceHTTP2   bool
+	DisableHTTP2 bool
+}
+
+// NewRESTClientWithOptions creates a new REST client with customized default headers
+func NewRESTClientWithOptions(baseURL string, auth AuthConfig, opts Options) (*RESTClient, error) {
+	if opts.ForceHTTP2 && opts.DisableHTTP2 {
+		return nil, fmt.Errorf("ForceHTTP2 and DisableHTTP2 are mutually exclusive")
+	}
+
+	client, err := NewRESTClient(baseURL, auth)
+	if err != nil {
+		return nil, err
+	}
+
+	if opts.DisableDefaultHeaders {
+		client.defaultHeaders = make(map[string]string)
+	}
+	if opts.UserAgent != "" {
+		client.defaultHeaders["User-Agent"] = opts.UserAgent
+	}
+	if opts.BasePath != "" {
+		client.WithBasePath(opts.BasePath)
+	}
+	if opts.RateLimit != nil {
+		client.WithRateLimit(*opts.RateLimit)
+	}
+	if opts.ForceHTTP2 {
+		client.ForceHTTP2()
+	}
+	if opts.DisableHTTP2 {
+		client.DisableHTTP2()
+	}
+
+	return client, nil
+}
+
+// NewRESTClientWithTimeout creates a new REST client with a client-wide default timeout.
+// RESTRequest.Timeout still overrides it per request.
+func NewRESTClientWithTimeout(baseURL string, auth AuthConfig, timeout time.Duration) (*RESTClient, error) {
+	client, err := NewRESTClient(baseURL, auth)
+	if err != nil {
+		return nil, err
+	}
+
+	client.httpClient.Timeout = timeout
+	if client.oauth2Client != nil {
+		client.oauth2Client.Timeout = timeout
+	}
+
+	return client, nil
+}
+
+// WithBasePath prepends a path prefix such as "/api/v2" to every endpoint resolved against
+// the client's base URL. Requests that set RESTRequest.BaseURL target another host and are
+// not prefixed, and absolute endpoint URLs bypass both the base URL and the prefix.
+func (c *RESTClient) WithBasePath(basePath string) *RESTClient {
+	c.basePath = strings.Trim(basePath, "/")
+	return c
+}
+
+// ExpectJSON configures the client to negotiate and decode JSON responses
+func (c *RESTClient) ExpectJSON() *RESTClient {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.defaultHeaders["Accept"] = string(JSONContentType)
+	c.responseFormat = FormatJSON
+	return c
+}
+
+// ExpectXML configures the client to negotiate and decode XML responses
+func (c *RESTClient) ExpectXML() *RESTClient {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.defaultHeaders["Accept"] = string(XMLContentType)
+	c.responseFormat = FormatXML
+	return c
+}
+
+// SetBaseURL points the client at a new base URL, e.g. to fail over to another host,
+// without re-running OAuth2 setup. It is safe to call while requests are in flight.
+func (c *RESTClient) SetBaseURL(baseURL string) *RESTClient {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.baseURL = strings.TrimSuffix(baseURL, "/")
+	return c
+}
+
+// SetDefaultHeader sets a header sent with every request; request headers still override it.
+// It is safe to call while other goroutines are executing requests.
+func (c *RESTClient) SetDefaultHeader(key, value string) *RESTClient {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.defaultHeaders[key] = value
+	return c
+}
+
+// RemoveDefaultHeader stops sending a default header
+func (c *RESTClient) RemoveDefaultHeader(key string) *RESTClient {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.defaultHeaders, key)
+	return c
+}
+
+// WithSuccessPredicate overrides the default 2xx check used by RESTResponse.IsSuccess,
+// e.g. to treat a 200 carrying an error header as a failure
+func (c *RESTClient) WithSuccessPredicate(predicate func(*RESTResponse) bool) *RESTClient {
+	c.successPredicate = predicate
+	return c
+}
+
+// OnStatus registers a handler that Execute runs when a response has the given status code,
+// e.g. to refresh credentials on 401 or alert on 503. An error from the handler is returned by
+// Execute in place of the response. Register handlers before issuing requests.
+func (c *RESTClient) OnStatus(code int, handler func(*RESTResponse) error) *RESTClient {
+	if c.statusHandlers == nil {
+		c.statusHandlers = make(map[int]func(*RESTResponse) error)
+	}
+	c.statusHandlers[code] = handler
+	return c
+}
+
+// EnableTracePropagation injects W3C traceparent/tracestate headers from the
+// span context on each request's ctx into outbound requests
+func (c *RESTClient) EnableTracePropagation() *RESTClient {
+	c.propagator = propagation.TraceContext{}
+	return c
+}
+
+// DisableKeepAlives closes the connection after every request instead of reusing it.
+// Useful for debugging connection-pool issues or servers with broken keep-alive.
+func (c *RESTClient) DisableKeepAlives() *RESTClient {
+	transport := c.cloneTransport()
+	transport.DisableKeepAlives = true
+
+	c.setTransport(transport)
+	c.disableKeepAlives = true
+	return c
+}
+
+// ForceHTTP2 attempts HTTP/2 over TLS even when the transport has a custom TLS config or
+// dialer, which otherwise makes Go fall back to HTTP/1.1. Servers that don't negotiate h2
+// via ALPN are still spoken to over HTTP/1.1. Many concurrent requests to one host then
+// share a single multiplexed connection.
+func (c *RESTClient) ForceHTTP2() *RESTClient {
+	transport := c.cloneTransport()
+	transport.ForceAttemptHTTP2 = true
+	transport.TLSNextProto = nil
+
+	c.setTransport(transport)
+	return c
+}
+
+// DisableHTTP2 restricts the client to HTTP/1.1, e.g. for servers with broken HTTP/2 support
+func (c *RESTClient) DisableHTTP2() *RESTClient {
+	transport := c.cloneTransport()
+	transport.ForceAttemptHTTP2 = false
+	// A non-nil, empty TLSNextProto map disables HTTP/2
+	transport.TLSNextProto = make(map[string]func(string, *tls.Conn) http.RoundTripper)
+
+	c.setTransport(transport)
+	return c
+}
+
+// WithLocalAddr makes outbound connections originate from the given local IP
+// (optionally "ip:port"), for multi-homed hosts or egress IP allowlists
+func (c *RESTClient) WithLocalAddr(localAddr string) (*RESTClient, error) {
+	host, port := localAddr, "0"
+	if h, p, err := net.SplitHostPort(localAddr); err == nil {
+		host, port = h, p
+	}
+
+	ip := net.ParseIP(host)
+	if ip == nil {
+		return nil, fmt.Errorf("invalid local address: %s", localAddr)
+	}
+	portNum, err := strconv.Atoi(port)
+	if err != nil {
+		return nil, fmt.Errorf("invalid local address port: %s", localAddr)
+	}
+
+	dialer := c.cloneDialer()
+	dialer.LocalAddr = &net.TCPAddr{IP: ip, Port: portNum}
+
+	transport := c.cloneTransport()
+	transport.DialContext = dialer.DialContext
+	c.setTransport(transport)
+	c.dialer = dialer
+	return c, nil
+}
+
+// TransportTimeouts bounds individual phases of a request. Unlike the overall client
+// timeout they do not cover reading the response body, so a slow stream is not cut off.
+// Zero values keep the transport's current setting.
+type TransportTimeouts struct {
+	DialTimeout           time.Duration `json:"dial_timeout,omitempty"`
+	TLSHandshakeTimeout   time.Duration `json:"tls_handshake_timeout,omitempty"`
+	ResponseHeaderTimeout time.Duration `json:"response_header_timeout,omitempty"`
+}
+
+// WithTransportTimeouts sets per-phase transport timeouts. For streaming endpoints, pair it
+// with NewRESTClientWithTimeout(..., 0) so only these phases are bounded.
+func (c *RESTClient) WithTransportTimeouts(timeouts TransportTimeouts) *RESTClient {
+	transport := c.cloneTransport()
+	if timeouts.DialTimeout > 0 {
+		dialer := c.cloneDialer()
+		dialer.Timeout = timeouts.DialTimeout
+		transport.DialContext = dialer.DialContext
+		c.dialer = dialer
+	}
+	if timeouts.TLSHandshakeTimeout > 0 {
+		transport.TLSHandshakeTimeout = timeouts.TLSHandshakeTimeout
+	}
+	if timeouts.ResponseHeaderTimeout > 0 {
+		transport.ResponseHeaderTimeout = timeouts.ResponseHeaderTimeout
+	}
+
+	c.setTransport(transport)
+	return c
+}
+
+// cloneDialer returns a copy of the client's current dialer so dial options compose
+func (c *RESTClient) cloneDialer() *net.Dialer {
+	if c.dialer != nil {
+		dialer := *c.dialer
+		return &dialer
+	}
+	return &net.Dialer{
+		Timeout:   30 * time.Second,
+		KeepAlive: 30 * time.Second,
+	}
+}
+
+// cloneTransport returns a copy of the client's current transport so options compose
+func (c *RESTClient) cloneTransport() *http.Transport {
+	if transport, ok := (*transportSlot(&c.httpClient.Transport)).(*http.Transport); ok {
+		return transport.Clone()
+	}
+	return http.DefaultTransport.(*http.Transport).Clone()
+}
+
+// setTransport installs the transport for both plain and OAuth2 requests, beneath any
+// recording or replay transport
+func (c *RESTClient) setTransport(transport *http.Transport) {
+	*transportSlot(&c.httpClient.Transport) = transport
+	if c.oauth2Client != nil {
+		if oauthTransport, ok := (*transportSlot(&c.oauth2Client.Transport)).(*oauth2.Transport); ok {
+			oauthTransport.Base = transport
+		}
+	}
+}
+
+// HTTPClient returns the underlying HTTP client used for requests.
+// The client is shared by all requests, so mutate it before issuing
+// concurrent calls rather than while they are in flight.
+func (c *RESTClient) HTTPClient() *http.Client {
+	if c.oauth2Client != nil {
+		return c.oauth2Client
+	}
+	return c.httpClient
+}
+
+// Close releases idle keep-alive connections held by the client's transports, including
+// the OAuth2 transport. In-flight requests are unaffected and the client remains usable.
+// A client without a custom transport shares http.DefaultTransport, whose idle connections
+// are closed as well.
+func (c *RESTClient) Close() {
+	c.httpClient.CloseIdleConnections()
+	if c.oauth2Client == nil {
+		return
+	}
+
+	c.oauth2Client.CloseIdleConnections()
+	// oauth2.Transport doesn't forward CloseIdleConnections to its base transport
+	if oauthTransport, ok := (*transportSlot(&c.oauth2Client.Transport)).(*oauth2.Transport); ok && oauthTransport.Base != nil {
+		if closer, ok := oauthTransport.Base.(interface{ CloseIdleConnections() }); ok {
+			closer.CloseIdleConnections()
+		}
+	}
+}
+
 // setupOAuth2 configures OAuth2 client credentials flow
 func (c *RESTClient) setupOAuth2() error {
-	if c.auth.ClientID == "" || c.auth.ClientSecret == "" || c.auth.TokenURL == "" {
-		return fmt.Errorf("OAuth2 requires client_id, client_secret, and token_url")
+	newSource, err := c.oauth2TokenSource()
+	if err != nil {
+		return err
 	}
 
-	config := &clientcredentials.Config{
-		ClientID:     c.auth.ClientID,
-		ClientSecret: c.auth.ClientSecret,
-		TokenURL:     c.auth.TokenURL,
-		Scopes:       c.auth.Scopes,
+	// Build the client around our own token source so a 401 can force a refresh
+	c.oauth2Source = &refreshableTokenSource{newSource: newSource}
+	c.oauth2Client = &http.Client{
+		Transport: &oauth2.Transport{Source: c.oauth2Source},
 	}
-
-	c.oauth2Client = config.Client(context.Background())
 	return nil
 }
 
+// oauth2TokenSource validates the OAuth2 settings for the configured grant and returns a
+// constructor for its token source. The constructor receives the last token issued, if any,
+// so a rotated refresh token is used after a forced refresh.
+func (c *RESTClient) oauth2TokenSource() (func(last *oauth2.Token) oauth2.TokenSource, error) {
+	auth := c.auth
+	switch auth.OAuth2Grant {
+	case "", OAuth2ClientCredentials:
+		if auth.ClientID == "" || auth.ClientSecret == "" || auth.TokenURL == "" {
+			return nil, fmt.Errorf("OAuth2 requires client_id, client_secret, and token_url")
+		}
+		config := &clientcredentials.Config{
+			ClientID:     auth.ClientID,
+			ClientSecret: auth.ClientSecret,
+			TokenURL:     auth.TokenURL,
+			Scopes:       auth.Scopes,
+		}
+		return func(*oauth2.Token) oauth2.TokenSource {
+			return config.TokenSource(context.Background())
+		}, nil
+
+	case OAuth2Password:
+		if auth.ClientID == "" || auth.TokenURL == "" || auth.Username == "" {
+			return nil, fmt.Errorf("OAuth2 password grant requires client_id, token_url, and username")
+		}
+		config := auth.oauth2Config()
+		return func(*oauth2.Token) oauth2.TokenSource {
+			// Request a new token with the owner's credentials whenever the cached one expires
+			return oauth2.ReuseTokenSource(nil, &passwordTokenSource{
+				config:   config,
+				username: auth.Username,
+				password: auth.Password,
+			})
+		}, nil
+
+	case OAuth2RefreshToken:
+		if auth.ClientID == "" || auth.TokenURL == "" || auth.RefreshToken == "" {
+			return nil, fmt.Errorf("OAuth2 refresh_token grant requires client_id, token_url, and refresh_token")
+		}
+		config := auth.oauth2Config()
+		return func(last *oauth2.Token) oauth2.TokenSource {
+			refreshToken := auth.RefreshToken
+			if last != nil && last.RefreshToken != "" {
+				refreshToken = last.RefreshToken
+			}
+			// A token with only a refresh token is expired, so the first use refreshes it
+			return config.TokenSource(context.Background(), &oauth2.Token{RefreshToken: refreshToken})
+		}, nil
+
+	default:
+		return nil, fmt.Errorf("unsupported OAuth2 grant: %s", auth.OAuth2Grant)
+	}
+}
+
+// oauth2Config builds the config shared by the password and refresh_token grants
+func (a AuthConfig) oauth2Config() *oauth2.Config {
+	return &oauth2.Config{
+		ClientID:     a.ClientID,
+		ClientSecret: a.ClientSecret,
+		Endpoint:     oauth2.Endpoint{TokenURL: a.TokenURL},
+		Scopes:       a.Scopes,
+	}
+}
+
+// passwordTokenSource requests tokens with the resource owner password grant
+type passwordTokenSource struct {
+	config   *oauth2.Config
+	username string
+	password string
+}
+
+func (s *passwordTokenSource) Token() (*oauth2.Token, error) {
+	return s.config.PasswordCredentialsToken(context.Background(), s.username, s.password)
+}
+
+// refreshableTokenSource caches OAuth2 tokens and can discard the cached token on demand
+type refreshableTokenSource struct {
+	newSource func(last *oauth2.Token) oauth2.TokenSource
+
+	mu      sync.Mutex
+	current oauth2.TokenSource
+	last    *oauth2.Token
+}
+
+// Token returns the cached token, fetching a new one when missing or expired
+func (s *refreshableTokenSource) Token() (*oauth2.Token, error) {
+	s.mu.Lock()
+	if s.current == nil {
+		s.current = s.newSource(s.last)
+	}
+	current := s.current
+	s.mu.Unlock()
+
+	token, err := current.Token()
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	s.last = token
+	s.mu.Unlock()
+	return token, nil
+}
+
+// refresh discards the cached token so the next request fetches a new one
+func (s *refreshableTokenSource) refresh() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.current = s.newSource(s.last)
+}
+
 // Execute performs REST API call
 func (c *RESTClient) Execute(ctx context.Context, req RESTRequest) (*RESTResponse, error) {
+	if c.Logger == nil {
+		return c.execute(ctx, req)
+	}
+
+	// Log the URL before authentication is applied so API keys never appear
+	loggedURL := c.buildURL(req.BaseURL, req.Endpoint, req.QueryParams, req.QueryParamsMulti, req.RawQuery)
+	c.Logger.LogAttrs(ctx, slog.LevelDebug, "Sending REST request",
+		slog.String("method", string(req.Method)),
+		slog.String("url", loggedURL))
+
+	resp, err := c.execute(ctx, req)
+	if err != nil {
+		c.Logger.LogAttrs(ctx, slog.LevelError, "REST request failed",
+			slog.String("method", string(req.Method)),
+			slog.String("url", loggedURL),
+			slog.String("error", c.redactSecrets(err.Error())))
+		return nil, err
+	}
+
+	c.Logger.LogAttrs(ctx, slog.LevelInfo, "Received REST response",
+		slog.String("method", string(req.Method)),
+		slog.String("url", loggedURL),
+		slog.Int("status", resp.StatusCode),
+		slog.Duration("duration", resp.Duration))
+	return resp, nil
+}
+
+// redactSecrets masks configured credentials in text such as transport errors,
+// which can embed the full request URL including API key query parameters
+func (c *RESTClient) redactSecrets(text string) string {
+	secrets := []string{c.auth.Password, c.auth.Token, c.auth.ClientSecret, c.auth.APIKey, c.auth.RefreshToken}
+	for _, value := range c.auth.ExtraAuthHeaders {
+		secrets = append(secrets, value)
+	}
+	for _, secret := range secrets {
+		if secret == "" {
+			continue
+		}
+		text = strings.ReplaceAll(text, secret, "[REDACTED]")
+		text = strings.ReplaceAll(text, url.QueryEscape(secret), "[REDACTED]")
+	}
+	return text
+}
+
+// RetryPolicy controls ExecuteWithRetry backoff
+type RetryPolicy struct {
+	MaxAttempts          int           `json:"max_attempts"`
+	InitialBackoff       time.Duration `json:"initial_backoff"`
+	BackoffMultiplier    float64       `json:"backoff_multiplier"`
+	MaxBackoff           time.Duration `json:"max_backoff"`
+	RetryableStatusCodes []int         `json:"retryable_status_codes,omitempty"` // Default: 5xx errors
+}
+
+// ExecuteWithRetry performs the request, retrying transport errors and retryable status codes
+// with exponential backoff. Once attempts are exhausted the last response is returned as-is,
+// so callers check IsSuccess just as with Execute. io.Reader bodies cannot be replayed, so
+// requests with one are sent once without retrying.
+func (c *RESTClient) ExecuteWithRetry(ctx context.Context, req RESTRequest, policy RetryPolicy) (*RESTResponse, error) {
+	maxAttempts := policy.MaxAttempts
+	if _, streamed := req.Body.(io.Reader); maxAttempts <= 0 || streamed {
+		maxAttempts = 1
+	}
+	backoff := policy.InitialBackoff
+	if backoff <= 0 {
+		backoff = time.Second
+	}
+	multiplier := policy.BackoffMultiplier
+	if multiplier <= 0 {
+		multiplier = 2.0
+	}
+
+	var lastErr error
+	for attempt := 1; attempt <= maxAttempts; attempt++ {
+		resp, err := c.Execute(ctx, req)
+		if err == nil && !policy.isRetryableStatus(resp.StatusCode) {
+			return resp, nil
+		}
+		if attempt == maxAttempts {
+			if err != nil {
+				return nil, fmt.Errorf("failed after %d attempts: %w", attempt, err)
+			}
+			return resp, nil
+		}
+		lastErr = err
+
+		select {
+		case <-ctx.Done():
+			if lastErr != nil {
+				return nil, fmt.Errorf("retry cancelled after %d attempts: %w", attempt, lastErr)
+			}
+			return nil, fmt.Errorf("retry cancelled after %d attempts: %w", attempt, ctx.Err())
+		case <-time.After(backoff):
+		}
+
+		backoff = time.Duration(float64(backoff) * multiplier)
+		if policy.MaxBackoff > 0 && backoff > policy.MaxBackoff {
+			backoff = policy.MaxBackoff
+		}
+	}
+
+	return nil, lastErr
+}
+
+// isRetryableStatus reports whether the policy retries a status code, defaulting to 5xx
+func (p RetryPolicy) isRetryableStatus(statusCode int) bool {
+	if len(p.RetryableStatusCodes) == 0 {
+		return statusCode >= 500
+	}
+	for _, code := range p.RetryableStatusCodes {
+		if code == statusCode {
+			return true
+		}
+	}
+	return false
+}
+
+// execute performs the REST API call without logging
+func (c *RESTClient) execute(ctx context.Context, req RESTRequest) (*RESTResponse, error) {
+	start := time.Now()
+
+	// Build full URL
+	fullURL := c.buildURL(req.BaseURL, req.Endpoint, req.QueryParams, req.QueryParamsMulti, req.RawQuery)
+
+	// Generate the request ID once so a retried request keeps the same ID
+	if c.GenerateRequestID && !c.hasHeader(req.Headers, requestIDHeader) {
+		requestID, err := newUUID()
+		if err != nil {
+			return nil, fmt.Errorf("failed to generate request ID: %w", err)
+		}
+		// Copy the headers so the caller's map is never mutated
+		headers := make(map[string]string, len(req.Headers)+1)
+		for key, value := range req.Headers {
+			headers[key] = value
+		}
+		headers[requestIDHeader] = requestID
+		req.Headers = headers
+	}
+
+	// Execute request
+	httpReq, httpResp, rawRequest, err := c.send(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	// A 401 with OAuth2 usually means the cached token expired mid-flight:
+	// refresh it and retry exactly once. Streamed bodies cannot be replayed.
+	if httpResp.StatusCode == http.StatusUnauthorized && c.oauth2Source != nil {
+		if _, streamed := req.Body.(io.Reader); !streamed {
+			httpResp.Body.Close()
+			c.oauth2Source.refresh()
+
+			httpReq, httpResp, rawRequest, err = c.send(ctx, req)
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+	defer httpResp.Body.Close()
+
+	// DumpResponse replaces the body with an in-memory copy, so it can still be read below
+	var rawResponse []byte
+	if c.CaptureWire {
+		rawResponse, err = httputil.DumpResponse(httpResp, true)
+		if err != nil {
+			return nil, fmt.Errorf("failed to capture response: %w", err)
+		}
+	}
+
+	// Read response body
+	body, err := io.ReadAll(httpResp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response body: %w", err)
+	}
+
+	// The transport leaves gzip responses encoded when Accept-Encoding was set explicitly
+	if len(body) > 0 && !httpResp.Uncompressed && requestedGzip(httpReq) &&
+		strings.EqualFold(httpResp.Header.Get("Content-Encoding"), "gzip") {
+		body, err = gunzipBody(body)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decompress response body: %w", err)
+		}
+		// Match the transport's own transparent decompression
+		httpResp.Header.Del("Content-Encoding")
+		httpResp.Header.Del("Content-Length")
+		httpResp.ContentLength = -1
+	}
+
+	c.mu.RLock()
+	format := c.responseFormat
+	c.mu.RUnlock()
+
+	// Build response
+	response := &RESTResponse{
+		StatusCode:    httpResp.StatusCode,
+		Status:        httpResp.Status,
+		Headers:       httpResp.Header,
+		Body:          body,
+		ContentType:   httpResp.Header.Get("Content-Type"),
+		ServerTiming:  httpResp.Header.Get("Server-Timing"),
+		ContentLength: httpResp.ContentLength,
+		Duration:      time.Since(start),
+		URL:           fullURL,
+		FinalURL:      httpResp.Request.URL.String(),
+		RequestID:     httpReq.Header.Get(requestIDHeader),
+		Request:       httpReq,
+		RawRequest:    rawRequest,
+		RawResponse:   rawResponse,
+		format:        format,
+
+		successPredicate:   c.successPredicate,
+		failureStatusCodes: c.FailureStatusCodes,
+		envelopeKey:        c.EnvelopeKey,
+	}
+
+	// Sniff content type when the server omits it
+	if req.SniffContentType && response.ContentType == "" {
+		if contentType, format, ok := sniffContentType(body); ok {
+			response.ContentType = contentType
+			response.format = format
+		}
+	}
+
+	if handler, ok := c.statusHandlers[response.StatusCode]; ok {
+		if err := handler(response); err != nil {
+			return nil, err
+		}
+	}
+
+	return response, nil
+}
+
+// requestIDHeader carries the ID set by GenerateRequestID
+const requestIDHeader = "X-Request-ID"
+
+// hasHeader reports whether the request headers or the client's default headers set a header
+func (c *RESTClient) hasHeader(headers map[string]string, name string) bool {
+	name = http.CanonicalHeaderKey(name)
+	for key, value := range headers {
+		if http.CanonicalHeaderKey(key) == name && value != "" {
+			return true
+		}
+	}
+
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	for key, value := range c.defaultHeaders {
+		if http.CanonicalHeaderKey(key) == name && value != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// newUUID returns a random RFC 4122 version 4 UUID
+func newUUID() (string, error) {
+	var b [16]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		return "", err
+	}
+	b[6] = (b[6] & 0x0f) | 0x40 // Version 4
+	b[8] = (b[8] & 0x3f) | 0x80 // RFC 4122 variant
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:16]), nil
+}
+
+// send builds the HTTP request and sends it with the appropriate HTTP client,
+// also returning the request's wire form when CaptureWire is enabled
+func (c *RESTClient) send(ctx context.Context, req RESTRequest) (*http.Request, *http.Response, []byte, error) {
+	// Prepare HTTP request
+	httpReq, err := c.BuildRequest(ctx, req)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	// DumpRequestOut replaces the body with an in-memory copy, so it can still be sent
+	var rawRequest []byte
+	if c.CaptureWire {
+		rawRequest, err = httputil.DumpRequestOut(httpReq, true)
+		if err != nil {
+			return nil, nil, nil, fmt.Errorf("failed to capture request: %w", err)
+		}
+	}
+
+	// Wait for the rate limiter
+	if c.limiter != nil {
+		if err := c.limiter.wait(ctx, req.Priority); err != nil {
+			return nil, nil, nil, fmt.Errorf("rate limiter wait cancelled: %w", err)
+		}
+	}
+
+	// Select HTTP client
+	client := c.selectHTTPClient(req.Timeout)
+
+	httpResp, err := client.Do(httpReq)
+	if err != nil {
+		if oauthErr := c.describeOAuth2Error(err); oauthErr != nil {
+			return nil, nil, nil, oauthErr
+		}
+		return nil, nil, nil, fmt.Errorf("failed to execute HTTP request: %w", err)
+	}
+	return httpReq, httpResp, rawRequest, nil
+}
+
+// OpenStream sends req like Execute, waiting for the rate limiter and logging the request,
+// but returns the response with its body unread so it can be consumed as it arrives. The
+// client's timeout and req.Timeout are not applied, since they would cut off a long body;
+// bound the request with ctx instead. The caller must close the response body.
+func (c *RESTClient) OpenStream(ctx context.Context, req RESTRequest) (*http.Response, error) {
+	if c.Logger == nil {
+		return c.openStream(ctx, req)
+	}
+
+	// Log the URL before authentication is applied so API keys never appear
+	loggedURL := c.buildURL(req.BaseURL, req.Endpoint, req.QueryParams, req.QueryParamsMulti, req.RawQuery)
+	c.Logger.LogAttrs(ctx, slog.LevelDebug, "Opening REST stream",
+		slog.String("method", string(req.Method)),
+		slog.String("url", loggedURL))
+
 	start := time.Now()
+	httpResp, err := c.openStream(ctx, req)
+	if err != nil {
+		c.Logger.LogAttrs(ctx, slog.LevelError, "REST request failed",
+			slog.String("method", string(req.Method)),
+			slog.String("url", loggedURL),
+			slog.String("error", c.redactSecrets(err.Error())))
+		return nil, err
+	}
+
+	c.Logger.LogAttrs(ctx, slog.LevelInfo, "Received REST response",
+		slog.String("method", string(req.Method)),
+		slog.String("url", loggedURL),
+		slog.Int("status", httpResp.StatusCode),
+		slog.Duration("duration", time.Since(start)))
+	return httpResp, nil
+}
+
+// openStream sends the request without logging or an overall timeout
+func (c *RESTClient) openStream(ctx context.Context, req RESTRequest) (*http.Response, error) {
+	httpReq, err := c.BuildRequest(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	if c.limiter != nil {
+		if err := c.limiter.wait(ctx, req.Priority); err != nil {
+			return nil, fmt.Errorf("rate limiter wait cancelled: %w", err)
+		}
+	}
 
+	// Keep the transport, including OAuth2, but drop the overall timeout
+	client := &http.Client{Transport: c.selectHTTPClient(0).Transport}
+
+	httpResp, err := client.Do(httpReq)
+	if err != nil {
+		if oauthErr := c.describeOAuth2Error(err); oauthErr != nil {
+			return nil, oauthErr
+		}
+		return nil, fmt.Errorf("failed to execute HTTP request: %w", err)
+	}
+	return httpResp, nil
+}
+
+// BuildRequest prepares the HTTP request Execute would send without sending it.
+// OAuth2 tokens are added by the transport at send time, so they are not included.
+func (c *RESTClient) BuildRequest(ctx context.Context, req RESTRequest) (*http.Request, error) {
 	// Build full URL
-	fullURL := c.buildURL(req.BaseURL, req.Endpoint, req.QueryParams)
+	fullURL := c.buildURL(req.BaseURL, req.Endpoint, req.QueryParams, req.QueryParamsMulti, req.RawQuery)
+
+	// Validate request body
+	if req.ValidateRequestBody {
+		if err := validateRequestBody(req.Body); err != nil {
+			return nil, fmt.Errorf("request body validation failed: %w", err)
+		}
+	}
 
 	// Prepare request body
 	var bodyReader io.Reader
-	if req.Body != nil {
+	compressed := false
+	if reader, ok := req.Body.(io.Reader); ok {
+		// Stream readers as-is instead of buffering them in memory
+		bodyReader = reader
+	} else if req.Body != nil {
 		bodyBytes, err := c.marshalRequestBody(req.Body, req.Headers)
 		if err != nil {
 			return nil, fmt.Errorf("failed to marshal request body: %w", err)
 		}
+
+		// Compress large bodies
+		if req.CompressBody && len(bodyBytes) > compressionThreshold {
+			bodyBytes, err = gzipBody(bodyBytes)
+			if err != nil {
+				return nil, fmt.Errorf("failed to compress request body: %w", err)
+			}
+			compressed = true
+		}
 		bodyReader = bytes.NewReader(bodyBytes)
 	}
 
@@ -158,43 +1121,106 @@ func (c *RESTClient) Execute(ctx context.Context, req RESTRequest) (*RESTRespons
 		return nil, fmt.Errorf("failed to create HTTP request: %w", err)
 	}
 
+	// Send Connection: close when keep-alives are disabled
+	httpReq.Close = c.disableKeepAlives
+
 	// Set headers
 	c.setRequestHeaders(httpReq, req.Headers)
+	if req.Accept != "" {
+		httpReq.Header.Set("Accept", string(req.Accept))
+	}
+	if compressed {
+		httpReq.Header.Set("Content-Encoding", "gzip")
+	}
+	if c.RequestGzipAcceptEncoding && httpReq.Header.Get("Accept-Encoding") == "" {
+		httpReq.Header.Set("Accept-Encoding", "gzip")
+	}
+
+	// Propagate trace context
+	if c.propagator != nil {
+		c.propagator.Inject(ctx, propagation.HeaderCarrier(httpReq.Header))
+	}
 
 	// Apply authentication
 	if err := c.applyAuthentication(httpReq, req.QueryParams); err != nil {
 		return nil, fmt.Errorf("failed to apply authentication: %w", err)
 	}
 
-	// Select HTTP client
-	client := c.selectHTTPClient(req.Timeout)
+	return httpReq, nil
+}
 
-	// Execute request
-	httpResp, err := client.Do(httpReq)
+// sensitiveHeaders are redacted when rendering requests
+var sensitiveHeaders = map[string]bool{
+	"Authorization":       true,
+	"Proxy-Authorization": true,
+	"Cookie":              true,
+	"X-Api-Key":           true,
+}
+
+// CurlCommand renders the request Execute would send as a curl command with secrets redacted
+func (c *RESTClient) CurlCommand(ctx context.Context, req RESTRequest) (string, error) {
+	httpReq, err := c.BuildRequest(ctx, req)
 	if err != nil {
-		return nil, fmt.Errorf("failed to execute HTTP request: %w", err)
+		return "", err
 	}
-	defer httpResp.Body.Close()
 
-	// Read response body
-	body, err := io.ReadAll(httpResp.Body)
-	if err != nil {
-		return nil, fmt.Errorf("failed to read response body: %w", err)
+	parts := []string{"curl", "-X", httpReq.Method, shellQuote(c.redactSecrets(httpReq.URL.String()))}
+
+	keys := make([]string, 0, len(httpReq.Header))
+	for key := range httpReq.Header {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		for _, value := range httpReq.Header[key] {
+			if sensitiveHeaders[key] || c.isAuthHeader(key) {
+				value = "[REDACTED]"
+			}
+			parts = append(parts, "-H", shellQuote(key+": "+value))
+		}
 	}
 
-	// Build response
-	response := &RESTResponse{
-		StatusCode:    httpResp.StatusCode,
-		Status:        httpResp.Status,
-		Headers:       httpResp.Header,
-		Body:          body,
-		ContentType:   httpResp.Header.Get("Content-Type"),
-		ContentLength: httpResp.ContentLength,
-		Duration:      time.Since(start),
-		URL:           fullURL,
+	if httpReq.Body != nil {
+		if httpReq.GetBody == nil || httpReq.Header.Get("Content-Encoding") != "" {
+			// Streamed or compressed bodies can't be shown inline
+			parts = append(parts, "--data-binary", "@-")
+		} else {
+			body, err := httpReq.GetBody()
+			if err != nil {
+				return "", fmt.Errorf("failed to read request body: %w", err)
+			}
+			bodyBytes, err := io.ReadAll(body)
+			if err != nil {
+				return "", fmt.Errorf("failed to read request body: %w", err)
+			}
+			parts = append(parts, "--data-raw", shellQuote(c.redactSecrets(string(bodyBytes))))
+		}
+	}
+
+	return strings.Join(parts, " "), nil
+}
+
+// shellQuote single-quotes a string for POSIX shells
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+// sniffContentType detects JSON or XML from the first non-whitespace byte of the body
+func sniffContentType(body []byte) (string, ResponseFormat, bool) {
+	trimmed := bytes.TrimLeft(body, " \t\r\n")
+	if len(trimmed) == 0 {
+		return "", "", false
 	}
 
-	return response, nil
+	switch trimmed[0] {
+	case '{', '[':
+		return string(JSONContentType), FormatJSON, true
+	case '<':
+		return string(XMLContentType), FormatXML, true
+	default:
+		return "", "", false
+	}
 }
 
 // GET performs HTTP GET request
@@ -206,6 +1232,17 @@ func (c *RESTClient) GET(ctx context.Context, endpoint string, queryParams map[s
 	})
 }
 
+// GETWithBody performs HTTP GET request with a request body, for search APIs such as
+// Elasticsearch that accept a query document on GET
+func (c *RESTClient) GETWithBody(ctx context.Context, endpoint string, body interface{}, queryParams map[string]string) (*RESTResponse, error) {
+	return c.Execute(ctx, RESTRequest{
+		Method:      GET,
+		Endpoint:    endpoint,
+		QueryParams: queryParams,
+		Body:        body,
+	})
+}
+
 // POST performs HTTP POST request
 func (c *RESTClient) POST(ctx context.Context, endpoint string, body interface{}) (*RESTResponse, error) {
 	return c.Execute(ctx, RESTRequest{
@@ -241,29 +1278,104 @@ func (c *RESTClient) DELETE(ctx context.Context, endpoint string) (*RESTResponse
 	})
 }
 
-// buildURL constructs the full URL
-func (c *RESTClient) buildURL(baseURL, endpoint string, queryParams map[string]string) string {
-	// Use provided baseURL or fallback to client's baseURL
-	if baseURL == "" {
-		baseURL = c.baseURL
+// DELETEWithBody performs HTTP DELETE request with a request body
+func (c *RESTClient) DELETEWithBody(ctx context.Context, endpoint string, body interface{}) (*RESTResponse, error) {
+	return c.Execute(ctx, RESTRequest{
+		Method:   DELETE,
+		Endpoint: endpoint,
+		Body:     body,
+	})
+}
+
+// GETPath performs HTTP GET request on a templated path such as "/users/{id}"
+func (c *RESTClient) GETPath(ctx context.Context, template string, params map[string]interface{}, queryParams map[string]string) (*RESTResponse, error) {
+	endpoint, err := expandPath(template, params)
+	if err != nil {
+		return nil, err
 	}
+	return c.GET(ctx, endpoint, queryParams)
+}
 
-	// Build full URL
-	fullURL := baseURL
-	if endpoint != "" {
-		endpoint = strings.TrimPrefix(endpoint, "/")
-		fullURL = fmt.Sprintf("%s/%s", baseURL, endpoint)
+// pathPlaceholder matches {name} placeholders in path templates
+var pathPlaceholder = regexp.MustCompile(`\{([^{}/]*)\}`)
+
+// expandPath substitutes {name} placeholders with URL-escaped parameter values
+func expandPath(template string, params map[string]interface{}) (string, error) {
+	var missing []string
+	expanded := pathPlaceholder.ReplaceAllStringFunc(template, func(placeholder string) string {
+		name := placeholder[1 : len(placeholder)-1]
+		value, ok := params[name]
+		if !ok || name == "" {
+			missing = append(missing, name)
+			return placeholder
+		}
+		return url.PathEscape(fmt.Sprint(value))
+	})
+
+	if len(missing) > 0 {
+		return "", fmt.Errorf("missing path parameters for template %s: %s", template, strings.Join(missing, ", "))
+	}
+	if strings.ContainsAny(expanded, "{}") {
+		return "", fmt.Errorf("unresolved placeholder in path template %s", template)
+	}
+	return expanded, nil
+}
+
+// PathSegments joins segments into an endpoint, escaping each one so spaces, slashes and
+// other reserved characters stay within their segment, e.g. PathSegments("files", "a/b")
+// is "/files/a%2Fb". Use it for endpoints passed to methods other than GETPath.
+func PathSegments(segments ...string) string {
+	var b strings.Builder
+	for _, segment := range segments {
+		b.WriteString("/")
+		b.WriteString(url.PathEscape(segment))
+	}
+	return b.String()
+}
+
+// buildURL constructs the full URL
+func (c *RESTClient) buildURL(baseURL, endpoint string, queryParams map[string]string, multiParams map[string][]string, rawQuery url.Values) string {
+	// Build full URL. Absolute endpoints are used as-is; the base path only
+	// applies to the client's baseURL, which request baseURL takes precedence over.
+	c.mu.RLock()
+	clientBaseURL := c.baseURL
+	c.mu.RUnlock()
+
+	var fullURL string
+	switch {
+	case isAbsoluteURL(endpoint):
+		fullURL = endpoint
+	case baseURL != "":
+		fullURL = joinURL(baseURL, endpoint)
+	case c.basePath != "":
+		fullURL = joinURL(joinURL(clientBaseURL, c.basePath), endpoint)
+	default:
+		fullURL = joinURL(clientBaseURL, endpoint)
 	}
 
 	// Add query parameters
-	if len(queryParams) > 0 {
+	if len(queryParams) > 0 || len(multiParams) > 0 || len(rawQuery) > 0 {
 		u, err := url.Parse(fullURL)
 		if err == nil {
 			q := u.Query()
 			for key, value := range queryParams {
 				q.Set(key, value)
 			}
-			u.RawQuery = q.Encode()
+			commaParams := make(map[string][]string)
+			for key, values := range multiParams {
+				if c.ArrayEncoding == ArrayComma {
+					q.Del(key)
+					commaParams[key] = values
+					continue
+				}
+				c.setArrayParam(q, key, values)
+			}
+			// Raw values are applied last so they win on conflicting keys
+			for key, values := range rawQuery {
+				q[key] = append([]string(nil), values...)
+				delete(commaParams, key)
+			}
+			u.RawQuery = encodeQuery(q, commaParams)
 			fullURL = u.String()
 		}
 	}
@@ -271,6 +1383,80 @@ func (c *RESTClient) buildURL(baseURL, endpoint string, queryParams map[string]s
 	return fullURL
 }
 
+// setArrayParam sets a multi-valued query parameter using the client's ArrayEncoding.
+// ArrayComma parameters are rendered by encodeQuery instead.
+func (c *RESTClient) setArrayParam(q url.Values, key string, values []string) {
+	switch c.ArrayEncoding {
+	case ArrayBrackets:
+		q[key+"[]"] = append([]string(nil), values...)
+	default:
+		q[key] = append([]string(nil), values...)
+	}
+}
+
+// encodeQuery encodes q like url.Values.Encode, adding each commaParams entry as a single
+// parameter whose escaped values are joined with a literal comma, e.g. a=1,2 rather than
+// a=1%2C2, which some servers don't split
+func encodeQuery(q url.Values, commaParams map[string][]string) string {
+	if len(commaParams) == 0 {
+		return q.Encode()
+	}
+
+	keys := make([]string, 0, len(q)+len(commaParams))
+	for key := range q {
+		keys = append(keys, key)
+	}
+	for key := range commaParams {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var buf strings.Builder
+	writeParam := func(key, escapedValue string) {
+		if buf.Len() > 0 {
+			buf.WriteByte('&')
+		}
+		buf.WriteString(url.QueryEscape(key))
+		buf.WriteByte('=')
+		buf.WriteString(escapedValue)
+	}
+	for _, key := range keys {
+		if values, ok := commaParams[key]; ok {
+			escaped := make([]string, len(values))
+			for i, value := range values {
+				escaped[i] = url.QueryEscape(value)
+			}
+			writeParam(key, strings.Join(escaped, ","))
+			continue
+		}
+		for _, value := range q[key] {
+			writeParam(key, url.QueryEscape(value))
+		}
+	}
+	return buf.String()
+}
+
+// isAbsoluteURL reports whether the endpoint is a full http(s) URL
+func isAbsoluteURL(endpoint string) bool {
+	lower := strings.ToLower(endpoint)
+	return strings.HasPrefix(lower, "http://") || strings.HasPrefix(lower, "https://")
+}
+
+// joinURL joins a base URL and an endpoint with exactly one slash between them
+func joinURL(base, endpoint string) string {
+	base = strings.TrimRight(base, "/")
+	endpoint = strings.TrimLeft(endpoint, "/")
+
+	switch {
+	case endpoint == "":
+		return base
+	case strings.HasPrefix(endpoint, "?"):
+		return base + endpoint
+	default:
+		return base + "/" + endpoint
+	}
+}
+
 // marshalRequestBody converts request body to bytes based on content type
 func (c *RESTClient) marshalRequestBody(body interface{}, headers map[string]string) ([]byte, error) {
 	if body == nil {
@@ -280,23 +1466,107 @@ func (c *RESTClient) marshalRequestBody(body interface{}, headers map[string]str
 	// Check content type
 	contentType := headers["Content-Type"]
 	if contentType == "" {
+		c.mu.RLock()
 		contentType = c.defaultHeaders["Content-Type"]
+		c.mu.RUnlock()
 	}
 
 	switch {
-	case strings.Contains(contentType, "application/json"):
-		return json.Marshal(body)
-	case strings.Contains(contentType, "application/x-www-form-urlencoded"):
+	case strings.Contains(contentType, string(JSONContentType)):
+		return c.marshalJSON(body)
+	case strings.Contains(contentType, string(FormContentType)):
 		return c.marshalFormData(body)
-	case strings.Contains(contentType, "text/plain"):
+	case isProtobufContentType(contentType):
+		if msg, ok := body.(proto.Message); ok {
+			return proto.Marshal(msg)
+		}
+		return nil, fmt.Errorf("request body must be a proto.Message for content type %s", contentType)
+	case strings.Contains(contentType, string(TextContentType)):
 		if str, ok := body.(string); ok {
 			return []byte(str), nil
 		}
 		return json.Marshal(body)
 	default:
 		// Default to JSON
-		return json.Marshal(body)
+		return c.marshalJSON(body)
+	}
+}
+
+// marshalJSON encodes a JSON body, canonicalizing it when the client requires it
+func (c *RESTClient) marshalJSON(body interface{}) ([]byte, error) {
+	encoded, err := json.Marshal(body)
+	if err != nil || !c.CanonicalJSON {
+		return encoded, err
+	}
+	return canonicalJSON(encoded)
+}
+
+// canonicalJSON re-encodes JSON with object keys sorted at every level, including
+// struct fields, and without whitespace. Numbers keep their original text.
+func canonicalJSON(data []byte) ([]byte, error) {
+	var value interface{}
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	if err := decoder.Decode(&value); err != nil {
+		return nil, err
+	}
+	// encoding/json writes map keys in sorted order
+	return json.Marshal(value)
+}
+
+// gzipBody compresses the request body
+func gzipBody(body []byte) ([]byte, error) {
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	if _, err := gz.Write(body); err != nil {
+		return nil, err
+	}
+	if err := gz.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// requestedGzip reports whether the request explicitly accepts a gzip response
+func requestedGzip(req *http.Request) bool {
+	for _, encoding := range strings.Split(req.Header.Get("Accept-Encoding"), ",") {
+		encoding, _, _ = strings.Cut(encoding, ";")
+		if strings.EqualFold(strings.TrimSpace(encoding), "gzip") {
+			return true
+		}
+	}
+	return false
+}
+
+// gunzipBody decompresses a gzip-encoded response body
+func gunzipBody(body []byte) ([]byte, error) {
+	gz, err := gzip.NewReader(bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	defer gz.Close()
+	return io.ReadAll(gz)
+}
+
+// validateRequestBody validates struct bodies against their `validate` tags
+func validateRequestBody(body interface{}) error {
+	if body == nil {
+		return nil
+	}
+
+	// Only structs carry validation tags
+	v := reflect.ValueOf(body)
+	for v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			return nil
+		}
+		v = v.Elem()
 	}
+	if v.Kind() != reflect.Struct {
+		return nil
+	}
+
+	return bodyValidator.Struct(v.Interface())
 }
 
 // marshalFormData converts body to form-encoded data
@@ -310,24 +1580,64 @@ func (c *RESTClient) marshalFormData(body interface{}) ([]byte, error) {
 		return nil, err
 	}
 
-	if err := json.Unmarshal(bodyBytes, &data); err != nil {
+	// UseNumber keeps large integers from turning into float notation
+	decoder := json.NewDecoder(bytes.NewReader(bodyBytes))
+	decoder.UseNumber()
+	if err := decoder.Decode(&data); err != nil {
 		return nil, err
 	}
 
-	// Add to form values
+	// Add to form values; slices become repeated keys (tags=a&tags=b)
 	for key, value := range data {
-		values.Set(key, fmt.Sprintf("%v", value))
+		if items, ok := value.([]interface{}); ok {
+			for _, item := range items {
+				encoded, err := formValue(item)
+				if err != nil {
+					return nil, fmt.Errorf("failed to encode form field %s: %w", key, err)
+				}
+				values.Add(key, encoded)
+			}
+			continue
+		}
+
+		encoded, err := formValue(value)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode form field %s: %w", key, err)
+		}
+		values.Set(key, encoded)
 	}
 
 	return []byte(values.Encode()), nil
 }
 
+// formValue renders a single form value, JSON-encoding nested objects and arrays
+func formValue(value interface{}) (string, error) {
+	switch v := value.(type) {
+	case nil:
+		return "", nil
+	case string:
+		return v, nil
+	case json.Number:
+		return v.String(), nil
+	case bool:
+		return strconv.FormatBool(v), nil
+	default:
+		encoded, err := json.Marshal(v)
+		if err != nil {
+			return "", err
+		}
+		return string(encoded), nil
+	}
+}
+
 // setRequestHeaders sets HTTP headers
 func (c *RESTClient) setRequestHeaders(req *http.Request, headers map[string]string) {
 	// Set default headers first
+	c.mu.RLock()
 	for key, value := range c.defaultHeaders {
 		req.Header.Set(key, value)
 	}
+	c.mu.RUnlock()
 
 	// Override with request-specific headers
 	for key, value := range headers {
@@ -339,19 +1649,34 @@ func (c *RESTClient) setRequestHeaders(req *http.Request, headers map[string]str
 func (c *RESTClient) applyAuthentication(req *http.Request, queryParams map[string]string) error {
 	switch c.auth.Type {
 	case NoAuth:
-		return nil
+		// Only ExtraAuthHeaders, if any
 
 	case BasicAuth:
-		if c.auth.Username == "" {
+		username, password := c.auth.Username, c.auth.Password
+		if c.auth.CredentialProvider != nil {
+			var err error
+			username, password, err = c.auth.CredentialProvider(req.Context())
+			if err != nil {
+				return fmt.Errorf("basic auth credential provider failed: %w", err)
+			}
+		} else if username == "" {
 			return fmt.Errorf("basic auth requires username")
 		}
-		req.SetBasicAuth(c.auth.Username, c.auth.Password)
+		req.SetBasicAuth(username, password)
 
 	case BearerAuth:
-		if c.auth.Token == "" {
+		token := c.auth.Token
+		if c.auth.TokenProvider != nil {
+			var err error
+			token, err = c.auth.TokenProvider(req.Context())
+			if err != nil {
+				return fmt.Errorf("bearer token provider failed: %w", err)
+			}
+		}
+		if token == "" {
 			return fmt.Errorf("bearer auth requires token")
 		}
-		req.Header.Set("Authorization", "Bearer "+c.auth.Token)
+		req.Header.Set("Authorization", "Bearer "+token)
 
 	case APIKeyAuth:
 		if c.auth.APIKey == "" {
@@ -374,15 +1699,80 @@ func (c *RESTClient) applyAuthentication(req *http.Request, queryParams map[stri
 
 	case OAuth2Auth:
 		// OAuth2 is handled by the oauth2Client
-		return nil
 
 	default:
 		return fmt.Errorf("unsupported authentication type: %s", c.auth.Type)
 	}
 
+	for key, value := range c.auth.ExtraAuthHeaders {
+		req.Header.Set(key, value)
+	}
 	return nil
 }
 
+// isAuthHeader reports whether a header carries credentials configured in AuthConfig
+func (c *RESTClient) isAuthHeader(key string) bool {
+	key = http.CanonicalHeaderKey(key)
+	if c.auth.KeyHeader != "" && key == http.CanonicalHeaderKey(c.auth.KeyHeader) {
+		return true
+	}
+	for name := range c.auth.ExtraAuthHeaders {
+		if key == http.CanonicalHeaderKey(name) {
+			return true
+		}
+	}
+	return false
+}
+
+// describeOAuth2Error translates token endpoint failures into descriptive errors
+func (c *RESTClient) describeOAuth2Error(err error) error {
+	if c.auth.Type != OAuth2Auth {
+		return nil
+	}
+
+	var retrieveErr *oauth2.RetrieveError
+	if !errors.As(err, &retrieveErr) {
+		return nil
+	}
+
+	statusCode := 0
+	if retrieveErr.Response != nil {
+		statusCode = retrieveErr.Response.StatusCode
+	}
+
+	message := retrieveErr.ErrorCode
+	if message == "" {
+		message = TruncateBody(bytes.TrimSpace(retrieveErr.Body), c.MaxLoggedBodyBytes)
+	}
+	if retrieveErr.ErrorDescription != "" {
+		message = fmt.Sprintf("%s: %s", message, retrieveErr.ErrorDescription)
+	}
+
+	return fmt.Errorf("OAuth2 token request to %s failed with HTTP %d: %s: %w",
+		c.auth.TokenURL, statusCode, message, err)
+}
+
+// DefaultMaxLoggedBodyBytes bounds bodies embedded in logs and error messages
+const DefaultMaxLoggedBodyBytes = 4096
+
+// TruncateBody renders a body for logs and error messages. Bodies longer than max bytes are
+// cut at a UTF-8 boundary and marked with an ellipsis and their full size. A max of zero or
+// less uses DefaultMaxLoggedBodyBytes.
+func TruncateBody(body []byte, max int) string {
+	if max <= 0 {
+		max = DefaultMaxLoggedBodyBytes
+	}
+	if len(body) <= max {
+		return string(body)
+	}
+
+	cut := max
+	for cut > 0 && !utf8.RuneStart(body[cut]) {
+		cut--
+	}
+	return fmt.Sprintf("%s... (%d bytes total)", body[:cut], len(body))
+}
+
 // selectHTTPClient returns appropriate HTTP client
 func (c *RESTClient) selectHTTPClient(timeout time.Duration) *http.Client {
 	if c.oauth2Client != nil {
@@ -409,8 +1799,20 @@ func (c *RESTClient) selectHTTPClient(timeout time.Duration) *http.Client {
 
 // Helper methods for RESTResponse
 
-// IsSuccess checks if the response indicates success (2xx status codes)
+// IsSuccess checks if the response indicates success (2xx status codes unless the client sets a success predicate).
+// The client's FailureStatusCodes are never successful.
 func (r *RESTResponse) IsSuccess() bool {
+	for _, code := range r.failureStatusCodes {
+		if code == r.StatusCode {
+			return false
+		}
+	}
+	if r.successPredicate != nil {
+		// Pass a copy without the predicate so it can call IsSuccess for the 2xx check
+		plain := *r
+		plain.successPredicate = nil
+		return r.successPredicate(&plain)
+	}
 	return r.StatusCode >= 200 && r.StatusCode < 300
 }
 
@@ -424,19 +1826,280 @@ func (r *RESTResponse) IsServerError() bool {
 	return r.StatusCode >= 500
 }
 
-// UnmarshalJSON unmarshals response body into provided interface
+// UnmarshalJSON unmarshals response body into provided interface.
+// 204/205 responses and empty bodies are a no-op and leave v untouched.
 func (r *RESTResponse) UnmarshalJSON(v interface{}) error {
+	if r.StatusCode == http.StatusNoContent || r.StatusCode == http.StatusResetContent || len(r.Body) == 0 {
+		return nil
+	}
 	if !strings.Contains(r.ContentType, "application/json") {
 		return fmt.Errorf("response content type is not JSON: %s", r.ContentType)
 	}
 	return json.Unmarshal(r.Body, v)
 }
 
+// StrictUnmarshalJSON unmarshals response body like UnmarshalJSON, but fails on fields
+// the target struct does not declare, for strict contract testing
+func (r *RESTResponse) StrictUnmarshalJSON(v interface{}) error {
+	if !strings.Contains(r.ContentType, "application/json") {
+		return fmt.Errorf("response content type is not JSON: %s", r.ContentType)
+	}
+	decoder := json.NewDecoder(bytes.NewReader(r.Body))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(v); err != nil {
+		// The decoder error names the field, e.g. json: unknown field "email"
+		return fmt.Errorf("strict JSON decode failed: %w", err)
+	}
+	return nil
+}
+
+// DecodeStream calls handler with each element of a JSON array body in order, decoding one
+// element at a time so a large array is never unmarshaled as a whole. The body itself is
+// already buffered; use RESTClient.StreamJSON to avoid holding it in memory. Handler errors
+// stop decoding and are returned as-is. 204/205 responses and empty bodies are a no-op.
+func (r *RESTResponse) DecodeStream(handler func(json.RawMessage) error) error {
+	if r.StatusCode == http.StatusNoContent || r.StatusCode == http.StatusResetContent || len(r.Body) == 0 {
+		return nil
+	}
+	if !strings.Contains(r.ContentType, "application/json") {
+		return fmt.Errorf("response content type is not JSON: %s", r.ContentType)
+	}
+	return decodeJSONArray(bytes.NewReader(r.Body), handler)
+}
+
+// StreamJSON sends req with OpenStream and calls handler with each element of the JSON array
+// response as it is read off the connection, so the body is never held in memory as a whole.
+// Handler errors stop decoding and are returned as-is. 204/205 responses and empty bodies
+// are a no-op and any other non-2xx status is an error. The client's timeout does not apply;
+// bound the request with ctx instead.
+func (c *RESTClient) StreamJSON(ctx context.Context, req RESTRequest, handler func(json.RawMessage) error) error {
+	httpResp, err := c.OpenStream(ctx, req)
+	if err != nil {
+		return err
+	}
+	defer httpResp.Body.Close()
+
+	if httpResp.StatusCode < 200 || httpResp.StatusCode >= 300 {
+		body, _ := io.ReadAll(io.LimitReader(httpResp.Body, 1024))
+		return fmt.Errorf("HTTP %d: %s", httpResp.StatusCode, strings.TrimSpace(string(body)))
+	}
+	if httpResp.StatusCode == http.StatusNoContent || httpResp.StatusCode == http.StatusResetContent {
+		return nil
+	}
+	body := bufio.NewReader(httpResp.Body)
+	if _, err := body.Peek(1); err == io.EOF {
+		return nil
+	}
+	if contentType := httpResp.Header.Get("Content-Type"); !strings.Contains(contentType, "application/json") {
+		return fmt.Errorf("response content type is not JSON: %s", contentType)
+	}
+
+	err = decodeJSONArray(body, handler)
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+	return err
+}
+
+// StreamStats measures a streaming consumer, e.g. to tell whether the handler rather than
+// the network is the bottleneck. Collect it by wrapping the handler with TrackStream.
+type StreamStats struct {
+	ElementsProcessed     int           // Handler invocations, including one that returned an error
+	TotalCallbackDuration time.Duration // Time spent inside the handler
+}
+
+// TrackStream wraps a DecodeStream, StreamJSON or StreamSSE handler so every invocation is
+// counted and timed in stats. Handlers are called sequentially, so stats needs no locking.
+func TrackStream[E any](stats *StreamStats, handler func(E) error) func(E) error {
+	return func(element E) error {
+		start := time.Now()
+		err := handler(element)
+		stats.TotalCallbackDuration += time.Since(start)
+		stats.ElementsProcessed++
+		return err
+	}
+}
+
+// decodeJSONArray reads a JSON array from reader, calling handler per element
+func decodeJSONArray(reader io.Reader, handler func(json.RawMessage) error) error {
+	decoder := json.NewDecoder(reader)
+
+	token, err := decoder.Token()
+	if err != nil {
+		return fmt.Errorf("failed to read JSON array: %w", err)
+	}
+	if delim, ok := token.(json.Delim); !ok || delim != '[' {
+		return fmt.Errorf("response body is not a JSON array")
+	}
+
+	for index := 0; decoder.More(); index++ {
+		var element json.RawMessage
+		if err := decoder.Decode(&element); err != nil {
+			return fmt.Errorf("failed to decode array element %d: %w", index, err)
+		}
+		if err := handler(element); err != nil {
+			return err
+		}
+	}
+
+	// Consume the closing bracket so a truncated array is reported
+	if _, err := decoder.Token(); err != nil {
+		return fmt.Errorf("failed to read JSON array: %w", err)
+	}
+	return nil
+}
+
+// DecodeJSON unmarshals a JSON response body into a value of type T
+func DecodeJSON[T any](resp *RESTResponse) (T, error) {
+	var result T
+	if !strings.Contains(resp.ContentType, "application/json") {
+		return result, fmt.Errorf("HTTP %d: response content type is not JSON: %s", resp.StatusCode, resp.ContentType)
+	}
+	if err := json.Unmarshal(resp.Body, &result); err != nil {
+		return result, fmt.Errorf("HTTP %d: failed to decode JSON response: %w", resp.StatusCode, err)
+	}
+	return result, nil
+}
+
+// Unmarshal decodes response body as JSON or XML according to the response's Content-Type,
+// so an API that answers in either format can be decoded with one call. A response without
+// a Content-Type is decoded using the format the client expects; other types are an error.
+func (r *RESTResponse) Unmarshal(v interface{}) error {
+	format := r.format
+	if r.ContentType != "" {
+		var ok bool
+		if format, ok = formatForContentType(r.ContentType); !ok {
+			return fmt.Errorf("unsupported response content type: %s", r.ContentType)
+		}
+	}
+
+	switch format {
+	case FormatXML:
+		return xml.Unmarshal(r.Body, v)
+	default:
+		return json.Unmarshal(r.Body, v)
+	}
+}
+
+// formatForContentType maps JSON and XML media types, including +json and +xml suffixes,
+// to their response format
+func formatForContentType(contentType string) (ResponseFormat, bool) {
+	media, _, _ := strings.Cut(contentType, ";")
+	media = strings.ToLower(strings.TrimSpace(media))
+
+	switch {
+	case media == "application/json" || strings.HasSuffix(media, "+json"):
+		return FormatJSON, true
+	case media == "application/xml" || media == "text/xml" || strings.HasSuffix(media, "+xml"):
+		return FormatXML, true
+	default:
+		return "", false
+	}
+}
+
+// UnmarshalProto unmarshals a protobuf response body into the provided message
+func (r *RESTResponse) UnmarshalProto(m proto.Message) error {
+	if !isProtobufContentType(r.ContentType) {
+		return fmt.Errorf("response content type is not protobuf: %s", r.ContentType)
+	}
+	return proto.Unmarshal(r.Body, m)
+}
+
+// isProtobufContentType reports whether the content type denotes a protobuf payload
+func isProtobufContentType(contentType string) bool {
+	return strings.Contains(contentType, "application/x-protobuf") ||
+		strings.Contains(contentType, "application/protobuf")
+}
+
+// UnmarshalData unmarshals the payload inside the response envelope into provided interface
+func (r *RESTResponse) UnmarshalData(v interface{}) error {
+	return UnmarshalEnvelope(r.Body, r.envelopeKey, v)
+}
+
+// UnmarshalEnvelope unmarshals the field at key of a JSON envelope into v.
+// An empty key uses DefaultEnvelopeKey.
+func UnmarshalEnvelope(body []byte, key string, v interface{}) error {
+	if key == "" {
+		key = DefaultEnvelopeKey
+	}
+
+	var envelope map[string]json.RawMessage
+	if err := json.Unmarshal(body, &envelope); err != nil {
+		return fmt.Errorf("failed to parse response envelope: %w", err)
+	}
+
+	data, exists := envelope[key]
+	if !exists {
+		return fmt.Errorf("response envelope has no '%s' field", key)
+	}
+	return json.Unmarshal(data, v)
+}
+
 // String returns response body as string
 func (r *RESTResponse) String() string {
 	return string(r.Body)
 }
 
+// Text returns response body as string if the content type is textual
+func (r *RESTResponse) Text() (string, error) {
+	if !isTextContentType(r.ContentType) {
+		return "", fmt.Errorf("response content type is not textual: %s", r.ContentType)
+	}
+	return string(r.Body), nil
+}
+
+// Cookies parses the cookies the server set via Set-Cookie headers
+func (r *RESTResponse) Cookies() []*http.Cookie {
+	return (&http.Response{Header: http.Header(r.Headers)}).Cookies()
+}
+
+// Cookie returns the cookie with the given name set by the server, if any
+func (r *RESTResponse) Cookie(name string) (*http.Cookie, bool) {
+	for _, cookie := range r.Cookies() {
+		if cookie.Name == name {
+			return cookie, true
+		}
+	}
+	return nil, false
+}
+
+// GetField returns a single JSON value by dotted path (e.g. "user.address.city" or "users.0.name")
+func (r *RESTResponse) GetField(path string) (interface{}, error) {
+	var current interface{}
+	if err := json.Unmarshal(r.Body, &current); err != nil {
+		return nil, fmt.Errorf("failed to parse JSON response: %w", err)
+	}
+
+	for _, key := range strings.Split(path, ".") {
+		switch node := current.(type) {
+		case map[string]interface{}:
+			value, exists := node[key]
+			if !exists {
+				return nil, fmt.Errorf("field '%s' not found in response", path)
+			}
+			current = value
+		case []interface{}:
+			index, err := strconv.Atoi(key)
+			if err != nil || index < 0 || index >= len(node) {
+				return nil, fmt.Errorf("invalid index '%s' in field path '%s'", key, path)
+			}
+			current = node[index]
+		default:
+			return nil, fmt.Errorf("field '%s' not found in response", path)
+		}
+	}
+
+	return current, nil
+}
+
+// isTextContentType checks if the content type carries textual data
+func isTextContentType(contentType string) bool {
+	contentType = strings.ToLower(contentType)
+	return strings.HasPrefix(contentType, "text/") ||
+		strings.Contains(contentType, "json") ||
+		strings.Contains(contentType, "xml")
+}
+
 // JSON returns response body as JSON string (formatted)
 func (r *RESTResponse) JSON() (string, error) {
 	var jsonData interface{}
@@ -0,0 +1,105 @@
+package restclient
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// EventStreamContentType is the media type of server-sent event streams
+const EventStreamContentType ContentType = "text/event-stream"
+
+// maxSSELineSize bounds a single line of an event stream
+const maxSSELineSize = 1024 * 1024
+
+// SSEEvent is a single server-sent event
+type SSEEvent struct {
+	ID    string // Last event ID seen on the stream, carried over from earlier events when unset
+	Event string // Event type, empty for the default "message" type
+	Data  string // Data lines joined with "\n"
+}
+
+// StreamSSE opens a server-sent event stream and calls handler for each event as it arrives,
+// until ctx is cancelled, the server closes the stream or handler returns an error.
+// The stream is opened with OpenStream, so it waits for the rate limiter and is logged, but
+// is not subject to the client's timeout; bound it with ctx instead.
+// A stream closed by the server returns nil; reconnecting is left to the caller.
+func (c *RESTClient) StreamSSE(ctx context.Context, endpoint string, query map[string]string, handler func(event SSEEvent) error) error {
+	httpResp, err := c.OpenStream(ctx, RESTRequest{
+		Method:      GET,
+		Endpoint:    endpoint,
+		QueryParams: query,
+		Headers:     map[string]string{"Cache-Control": "no-cache"},
+		Accept:      EventStreamContentType,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to open event stream: %w", err)
+	}
+	defer httpResp.Body.Close()
+
+	if httpResp.StatusCode < 200 || httpResp.StatusCode >= 300 {
+		body, _ := io.ReadAll(io.LimitReader(httpResp.Body, 1024))
+		return fmt.Errorf("event stream returned HTTP %d: %s", httpResp.StatusCode, strings.TrimSpace(string(body)))
+	}
+
+	err = readSSE(httpResp.Body, handler)
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+	return err
+}
+
+// readSSE parses an event stream per the WHATWG server-sent events format
+func readSSE(r io.Reader, handler func(event SSEEvent) error) error {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 4096), maxSSELineSize)
+
+	var (
+		lastID    string
+		eventType string
+		data      []string
+	)
+	for scanner.Scan() {
+		line := strings.TrimSuffix(scanner.Text(), "\r")
+
+		// A blank line dispatches the buffered event
+		if line == "" {
+			if len(data) > 0 {
+				event := SSEEvent{ID: lastID, Event: eventType, Data: strings.Join(data, "\n")}
+				if err := handler(event); err != nil {
+					return err
+				}
+			}
+			eventType = ""
+			data = nil
+			continue
+		}
+
+		// Lines starting with a colon are comments, often sent as keep-alives
+		if strings.HasPrefix(line, ":") {
+			continue
+		}
+
+		field, value, _ := strings.Cut(line, ":")
+		value = strings.TrimPrefix(value, " ")
+		switch field {
+		case "event":
+			eventType = value
+		case "data":
+			data = append(data, value)
+		case "id":
+			// IDs containing NUL are ignored per the spec
+			if !strings.Contains(value, "\x00") {
+				lastID = value
+			}
+		}
+	}
+
+	// An event without a terminating blank line is incomplete and discarded
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("failed to read event stream: %w", err)
+	}
+	return nil
+}
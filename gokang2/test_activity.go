@@ -1,17 +1,33 @@
 package activities
 
 import (
+	"bytes"
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand"
 	"net/http"
 	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"go.temporal.io/sdk/activity"
+	"go.temporal.io/sdk/converter"
 	"go.temporal.io/sdk/log"
+	"go.temporal.io/sdk/temporal"
 	"go.temporal.io/sdk/testsuite"
+	"go.temporal.io/sdk/worker"
 
 	"your-module/restclient" // Replace with your actual module path
 )
@@ -254,241 +270,2331 @@ func TestRESTServiceActivities_InvokeRESTService(t *testing.T) {
 	}
 }
 
-func TestRESTServiceActivities_InvokeRESTServiceWithRetry(t *testing.T) {
-	server := createTestServer(t)
+func TestRESTServiceActivities_RegisterStruct(t *testing.T) {
+	testSuite := &testsuite.WorkflowTestSuite{}
+	env := testSuite.NewTestActivityEnvironment()
+
+	// Every exported method must have an activity signature for the struct to register
+	assert.NotPanics(t, func() {
+		env.RegisterActivity(NewRESTServiceActivities(&testLogger{}))
+	})
+}
+
+func TestRESTServiceActivities_ZeroValue(t *testing.T) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&calls, 1) == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"status":"ok"}`))
+	}))
 	defer server.Close()
 
 	testSuite := &testsuite.WorkflowTestSuite{}
 	env := testSuite.NewTestActivityEnvironment()
 
-	activities := NewRESTServiceActivities(&testLogger{})
-	env.RegisterActivity(activities.InvokeRESTService)
-	env.RegisterActivity(activities.InvokeRESTServiceWithRetry)
+	// A zero value must work without NewRESTServiceActivities
+	activities := &RESTServiceActivities{}
+	env.RegisterActivity(activities)
 
-	tests := []struct {
-		name          string
-		request       RESTServiceRequest
-		expectSuccess bool
-		expectedRetries int
-		checkResponse func(t *testing.T, resp *RESTServiceResponse)
-	}{
-		{
-			name: "Success after retries",
-			request: RESTServiceRequest{
-				ServiceName: "RetryService",
-				BaseURL:     server.URL,
-				Auth:        restclient.AuthConfig{Type: restclient.NoAuth},
-				Request: restclient.RESTRequest{
-					Method:   restclient.GET,
-					Endpoint: "/retry-test",
-				},
-				Retry: &RetryConfig{
-					MaxAttempts:          3,
-					InitialBackoff:       100 * time.Millisecond,
-					BackoffMultiplier:    2.0,
-					RetryableStatusCodes: []int{500},
-				},
-			},
-			expectSuccess: true,
-			expectedRetries: 2, // Failed 2 times, succeeded on 3rd
-			checkResponse: func(t *testing.T, resp *RESTServiceResponse) {
-				var response map[string]interface{}
-				err := json.Unmarshal([]byte(resp.Body), &response)
-				assert.NoError(t, err)
-				assert.Equal(t, "success after retry", response["message"])
-			},
-		},
-		{
-			name: "Non-retryable error",
-			request: RESTServiceRequest{
-				ServiceName: "BadRequestService",
-				BaseURL:     server.URL,
-				Auth:        restclient.AuthConfig{Type: restclient.NoAuth},
-				Request: restclient.RESTRequest{
-					Method:   restclient.GET,
-					Endpoint: "/error/400",
-				},
-				Retry: &RetryConfig{
-					MaxAttempts:          3,
-					InitialBackoff:       100 * time.Millisecond,
-					RetryableStatusCodes: []int{500, 429}, // 400 not in list
-				},
-			},
-			expectSuccess: false,
-			expectedRetries: 0, // Should not retry 400 errors
-			checkResponse: func(t *testing.T, resp *RESTServiceResponse) {
-				assert.Equal(t, 400, resp.StatusCode)
-				assert.Contains(t, resp.Body, "Bad Request")
-			},
+	req := RESTServiceRequest{
+		ServiceName: "ZeroValueService",
+		BaseURL:     server.URL,
+		Auth:        restclient.AuthConfig{Type: restclient.NoAuth},
+		Request: restclient.RESTRequest{
+			Method:   restclient.GET,
+			Endpoint: "/status",
 		},
-		{
-			name: "Max retries exceeded",
-			request: RESTServiceRequest{
-				ServiceName: "FailingService",
-				BaseURL:     server.URL,
-				Auth:        restclient.AuthConfig{Type: restclient.NoAuth},
-				Request: restclient.RESTRequest{
-					Method:   restclient.GET,
-					Endpoint: "/error/500",
-				},
-				Retry: &RetryConfig{
-					MaxAttempts:          2,
-					InitialBackoff:       50 * time.Millisecond,
-					RetryableStatusCodes: []int{500},
-				},
-			},
-			expectSuccess: false,
-			expectedRetries: 1, // 1 retry (2 total attempts)
-			checkResponse: func(t *testing.T, resp *RESTServiceResponse) {
-				assert.Equal(t, 500, resp.StatusCode)
-				assert.Contains(t, resp.ErrorMessage, "All 2 attempts failed")
-			},
+		Retry: &RetryConfig{
+			MaxAttempts:    3,
+			InitialBackoff: 10 * time.Millisecond,
 		},
 	}
 
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			val, err := env.ExecuteActivity(activities.InvokeRESTServiceWithRetry, tt.request)
+	val, err := env.ExecuteActivity(activities.InvokeRESTServiceWithRetry, req)
+	require.NoError(t, err)
 
-			assert.NoError(t, err)
+	var response RESTServiceResponse
+	require.NoError(t, val.Get(&response))
+	assert.True(t, response.Success)
+	assert.Equal(t, 1, response.Retries)
 
-			var response RESTServiceResponse
-			err = val.Get(&response)
-			assert.NoError(t, err)
+	_, err = env.ExecuteActivity(activities.ValidateBodyPattern, &response, `"status":"ok"`)
+	assert.NoError(t, err)
 
-			assert.Equal(t, tt.expectSuccess, response.Success)
-			assert.Equal(t, tt.expectedRetries, response.Retries)
+	snapshot := activities.metrics.Snapshot()
+	assert.Equal(t, int64(2), snapshot.TotalRequests)
+	assert.Equal(t, int64(1), snapshot.Retries)
+}
 
-			if tt.checkResponse != nil {
-				tt.checkResponse(t, &response)
-			}
-		})
-	}
+func TestRESTServiceResponse_UnmarshalData(t *testing.T) {
+	resp := &RESTServiceResponse{Body: `{"data":{"id":1,"name":"John Doe"},"meta":{}}`}
+
+	var user TestUser
+	require.NoError(t, resp.UnmarshalData(&user))
+	assert.Equal(t, "John Doe", user.Name)
+
+	resp.Body = `{"id":1,"name":"John Doe"}`
+	err := resp.UnmarshalData(&user)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "no 'data' field")
 }
 
-func TestRESTServiceActivities_CRUDOperations(t *testing.T) {
-	server := createTestServer(t)
+func TestRESTServiceActivities_ResponseTransform(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/wrapped":
+			w.Write([]byte(`{"data":{"id":1,"name":"John Doe","email":"john@example.com"},"meta":{"version":"v1"}}`))
+		case "/list":
+			w.Write([]byte(`[{"id":1,"name":"John Doe"},{"id":2,"name":"Jane Smith"}]`))
+		}
+	}))
 	defer server.Close()
 
 	testSuite := &testsuite.WorkflowTestSuite{}
 	env := testSuite.NewTestActivityEnvironment()
 
 	activities := NewRESTServiceActivities(&testLogger{})
-	env.RegisterActivity(activities.GetResource)
-	env.RegisterActivity(activities.CreateResource)
-	env.RegisterActivity(activities.UpdateResource)
-	env.RegisterActivity(activities.DeleteResource)
-
-	auth := restclient.AuthConfig{Type: restclient.NoAuth}
+	env.RegisterActivity(activities.InvokeRESTService)
 
-	t.Run("GetResource", func(t *testing.T) {
-		val, err := env.ExecuteActivity(
-			activities.GetResource,
-			"UserService",
-			server.URL,
-			"/users/1",
-			auth,
-			nil,
-		)
+	newRequest := func(endpoint, transform string) RESTServiceRequest {
+		return RESTServiceRequest{
+			ServiceName: "UserService",
+			BaseURL:     server.URL,
+			Auth:        restclient.AuthConfig{Type: restclient.NoAuth},
+			Request: restclient.RESTRequest{
+				Method:   restclient.GET,
+				Endpoint: endpoint,
+			},
+			ResponseTransform: transform,
+		}
+	}
 
-		assert.NoError(t, err)
+	t.Run("Extract data field", func(t *testing.T) {
+		val, err := env.ExecuteActivity(activities.InvokeRESTService, newRequest("/wrapped", ExtractDataFieldTransform))
+		require.NoError(t, err)
 
 		var response RESTServiceResponse
 		err = val.Get(&response)
 		assert.NoError(t, err)
-
 		assert.True(t, response.Success)
-		assert.Equal(t, 200, response.StatusCode)
 
 		var user TestUser
 		err = json.Unmarshal([]byte(response.Body), &user)
 		assert.NoError(t, err)
 		assert.Equal(t, 1, user.ID)
+		assert.Equal(t, "John Doe", user.Name)
+		assert.NotContains(t, response.Body, "meta")
 	})
 
-	t.Run("CreateResource", func(t *testing.T) {
-		newUser := TestUser{
-			Name:  "Bob Wilson",
-			Email: "bob@example.com",
-		}
+	t.Run("First array element", func(t *testing.T) {
+		val, err := env.ExecuteActivity(activities.InvokeRESTService, newRequest("/list", FirstArrayElementTransform))
+		require.NoError(t, err)
 
-		val, err := env.ExecuteActivity(
-			activities.CreateResource,
-			"UserService",
-			server.URL,
-			"/users",
-			auth,
-			newUser,
-		)
+		var response RESTServiceResponse
+		err = val.Get(&response)
+		assert.NoError(t, err)
 
+		var user TestUser
+		err = json.Unmarshal([]byte(response.Body), &user)
 		assert.NoError(t, err)
+		assert.Equal(t, 1, user.ID)
+	})
+
+	t.Run("Unknown transform", func(t *testing.T) {
+		_, err := env.ExecuteActivity(activities.InvokeRESTService, newRequest("/wrapped", "does_not_exist"))
+
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "unknown response transform")
+	})
+}
+
+func TestRESTServiceActivities_SuccessPredicate(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Status", "error")
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]string{"message": "upstream failed"})
+	}))
+	defer server.Close()
+
+	testSuite := &testsuite.WorkflowTestSuite{}
+	env := testSuite.NewTestActivityEnvironment()
+
+	registry := NewRegistry()
+	registry.RegisterSuccessPredicate("x_status_header", func(resp *restclient.RESTResponse) bool {
+		return resp.IsSuccess() && http.Header(resp.Headers).Get("X-Status") != "error"
+	})
+	activities := NewRESTServiceActivitiesWithOptions(&testLogger{}, RESTServiceActivitiesOptions{Registry: registry})
+	env.RegisterActivity(activities.InvokeRESTService)
+
+	newRequest := func(predicate string) RESTServiceRequest {
+		return RESTServiceRequest{
+			ServiceName: "UpstreamService",
+			BaseURL:     server.URL,
+			Auth:        restclient.AuthConfig{Type: restclient.NoAuth},
+			Request: restclient.RESTRequest{
+				Method:   restclient.GET,
+				Endpoint: "/status",
+			},
+			SuccessPredicate: predicate,
+		}
+	}
+
+	t.Run("Error header marks failure", func(t *testing.T) {
+		val, err := env.ExecuteActivity(activities.InvokeRESTService, newRequest("x_status_header"))
+		require.NoError(t, err)
 
 		var response RESTServiceResponse
-		err = val.Get(&response)
-		assert.NoError(t, err)
+		require.NoError(t, val.Get(&response))
+		assert.Equal(t, 200, response.StatusCode)
+		assert.False(t, response.Success)
+		assert.Contains(t, response.ErrorMessage, "x_status_header")
+	})
+
+	t.Run("Default 2xx check", func(t *testing.T) {
+		val, err := env.ExecuteActivity(activities.InvokeRESTService, newRequest(""))
+		require.NoError(t, err)
 
+		var response RESTServiceResponse
+		require.NoError(t, val.Get(&response))
 		assert.True(t, response.Success)
-		assert.Equal(t, 201, response.StatusCode)
+	})
 
-		var createdUser TestUser
-		err = json.Unmarshal([]byte(response.Body), &createdUser)
-		assert.NoError(t, err)
-		assert.Equal(t, 123, createdUser.ID)
-		assert.Equal(t, "Bob Wilson", createdUser.Name)
+	t.Run("Unknown predicate", func(t *testing.T) {
+		_, err := env.ExecuteActivity(activities.InvokeRESTService, newRequest("does_not_exist"))
+
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "unknown success predicate")
 	})
+}
 
-	t.Run("UpdateResource", func(t *testing.T) {
-		updateUser := TestUser{
-			Name:  "John Updated",
-			Email: "john.updated@example.com",
+func TestRESTServiceActivities_Classifier(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/invalid":
+			w.WriteHeader(http.StatusUnprocessableEntity)
+			w.Write([]byte(`{"error":"email is invalid"}`))
+		case "/busy":
+			w.WriteHeader(http.StatusConflict)
+			w.Write([]byte(`{"error":"resource locked"}`))
+		default:
+			w.Write([]byte(`{"id":1}`))
 		}
+	}))
+	defer server.Close()
 
-		val, err := env.ExecuteActivity(
-			activities.UpdateResource,
-			"UserService",
-			server.URL,
-			"/users/1",
-			auth,
-			updateUser,
-		)
+	testSuite := &testsuite.WorkflowTestSuite{}
+	env := testSuite.NewTestActivityEnvironment()
 
-		assert.NoError(t, err)
+	registry := NewRegistry()
+	registry.RegisterErrorClassifier("validation", func(resp *RESTServiceResponse) (bool, error) {
+		switch resp.StatusCode {
+		case http.StatusUnprocessableEntity:
+			return false, fmt.Errorf("validation failed: %s", resp.Body)
+		case http.StatusConflict:
+			return true, errors.New("resource locked")
+		}
+		return false, nil
+	})
+	metrics := NewMetrics()
+	activities := NewRESTServiceActivitiesWithOptions(&testLogger{}, RESTServiceActivitiesOptions{
+		Registry: registry,
+		Metrics:  metrics,
+	})
+	env.RegisterActivity(activities.InvokeRESTService)
 
-		var response RESTServiceResponse
-		err = val.Get(&response)
-		assert.NoError(t, err)
+	newRequest := func(endpoint, classifier string) RESTServiceRequest {
+		return RESTServiceRequest{
+			ServiceName: "UserService",
+			BaseURL:     server.URL,
+			Auth:        restclient.AuthConfig{Type: restclient.NoAuth},
+			Request: restclient.RESTRequest{
+				Method:   restclient.POST,
+				Endpoint: endpoint,
+				Body:     map[string]string{"email": "not-an-email"},
+			},
+			Classifier: classifier,
+		}
+	}
+
+	t.Run("Classified 422 is non-retryable", func(t *testing.T) {
+		_, err := env.ExecuteActivity(activities.InvokeRESTService, newRequest("/invalid", "validation"))
+		require.Error(t, err)
+
+		var appErr *temporal.ApplicationError
+		require.True(t, errors.As(err, &appErr))
+		assert.True(t, appErr.NonRetryable())
+		assert.Equal(t, PermanentFailureErrorType, appErr.Type())
+		assert.Contains(t, appErr.Error(), "email is invalid")
+	})
+
+	t.Run("Retryable classification", func(t *testing.T) {
+		_, err := env.ExecuteActivity(activities.InvokeRESTService, newRequest("/busy", "validation"))
+		require.Error(t, err)
+
+		var appErr *temporal.ApplicationError
+		require.True(t, errors.As(err, &appErr))
+		assert.False(t, appErr.NonRetryable())
+		assert.Contains(t, appErr.Error(), "resource locked")
+	})
 
+	t.Run("Unclassified response", func(t *testing.T) {
+		val, err := env.ExecuteActivity(activities.InvokeRESTService, newRequest("/users", "validation"))
+		require.NoError(t, err)
+
+		var response RESTServiceResponse
+		require.NoError(t, val.Get(&response))
 		assert.True(t, response.Success)
-		assert.Equal(t, 200, response.StatusCode)
+	})
 
-		var updatedUser TestUser
-		err = json.Unmarshal([]byte(response.Body), &updatedUser)
-		assert.NoError(t, err)
-		assert.Equal(t, 1, updatedUser.ID)
-		assert.Equal(t, "John Updated", updatedUser.Name)
+	t.Run("Unknown classifier", func(t *testing.T) {
+		_, err := env.ExecuteActivity(activities.InvokeRESTService, newRequest("/users", "does_not_exist"))
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "unknown error classifier")
+	})
+
+	t.Run("Retry loop stops on permanent failures", func(t *testing.T) {
+		env.RegisterActivity(activities.InvokeRESTServiceWithRetry)
+		metrics.Reset()
+
+		req := newRequest("/invalid", "validation")
+		req.Retry = &RetryConfig{
+			MaxAttempts:          3,
+			InitialBackoff:       time.Millisecond,
+			RetryableStatusCodes: []int{http.StatusUnprocessableEntity},
+		}
+		_, err := env.ExecuteActivity(activities.InvokeRESTServiceWithRetry, req)
+		require.Error(t, err)
+		assert.Equal(t, int64(1), metrics.Snapshot().TotalRequests, "permanent failures are not retried")
+	})
+}
+
+func TestRESTServiceActivities_FailureStatusCodes(t *testing.T) {
+	attempts := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusPartialContent)
+		json.NewEncoder(w).Encode(map[string]string{"message": "partial results"})
+	}))
+	defer server.Close()
+
+	testSuite := &testsuite.WorkflowTestSuite{}
+	env := testSuite.NewTestActivityEnvironment()
+
+	activities := NewRESTServiceActivities(&testLogger{})
+	env.RegisterActivity(activities.InvokeRESTService)
+	env.RegisterActivity(activities.InvokeRESTServiceWithRetry)
+
+	req := RESTServiceRequest{
+		ServiceName: "SearchService",
+		BaseURL:     server.URL,
+		Auth:        restclient.AuthConfig{Type: restclient.NoAuth},
+		Request: restclient.RESTRequest{
+			Method:   restclient.GET,
+			Endpoint: "/search",
+		},
+		FailureStatusCodes: []int{http.StatusPartialContent},
+	}
+
+	t.Run("206 marked as failure", func(t *testing.T) {
+		val, err := env.ExecuteActivity(activities.InvokeRESTService, req)
+		require.NoError(t, err)
+
+		var response RESTServiceResponse
+		require.NoError(t, val.Get(&response))
+		assert.Equal(t, 206, response.StatusCode)
+		assert.False(t, response.Success)
+		assert.Contains(t, response.ErrorMessage, "configured as a failure status")
+	})
+
+	t.Run("206 retried when retryable", func(t *testing.T) {
+		attempts = 0
+		retryReq := req
+		retryReq.Retry = &RetryConfig{
+			MaxAttempts:          3,
+			InitialBackoff:       10 * time.Millisecond,
+			RetryableStatusCodes: []int{http.StatusPartialContent},
+		}
+
+		_, err := env.ExecuteActivity(activities.InvokeRESTServiceWithRetry, retryReq)
+		require.Error(t, err)
+		assert.Equal(t, 3, attempts)
+	})
+
+	t.Run("206 succeeds by default", func(t *testing.T) {
+		defaultReq := req
+		defaultReq.FailureStatusCodes = nil
+
+		val, err := env.ExecuteActivity(activities.InvokeRESTService, defaultReq)
+		require.NoError(t, err)
+
+		var response RESTServiceResponse
+		require.NoError(t, val.Get(&response))
+		assert.True(t, response.Success)
+	})
+}
+
+func TestRESTServiceActivities_ErrorBodyTruncation(t *testing.T) {
+	largeBody := `{"error":"` + strings.Repeat("x", 10000) + `"}`
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		if r.URL.Path == "/small" {
+			w.Write([]byte(`{"error":"bad input"}`))
+			return
+		}
+		w.Write([]byte(largeBody))
+	}))
+	defer server.Close()
+
+	newRequest := func(endpoint string) RESTServiceRequest {
+		return RESTServiceRequest{
+			ServiceName: "NoisyService",
+			BaseURL:     server.URL,
+			Auth:        restclient.AuthConfig{Type: restclient.NoAuth},
+			Request: restclient.RESTRequest{
+				Method:   restclient.GET,
+				Endpoint: endpoint,
+			},
+		}
+	}
+	invoke := func(t *testing.T, activities *RESTServiceActivities, endpoint string) RESTServiceResponse {
+		testSuite := &testsuite.WorkflowTestSuite{}
+		env := testSuite.NewTestActivityEnvironment()
+		env.RegisterActivity(activities.InvokeRESTService)
+
+		val, err := env.ExecuteActivity(activities.InvokeRESTService, newRequest(endpoint))
+		require.NoError(t, err)
+
+		var response RESTServiceResponse
+		require.NoError(t, val.Get(&response))
+		require.False(t, response.Success)
+		return response
+	}
+
+	t.Run("Large body is truncated by default", func(t *testing.T) {
+		response := invoke(t, NewRESTServiceActivities(&testLogger{}), "/large")
+
+		assert.Contains(t, response.ErrorMessage, "HTTP 400")
+		assert.Contains(t, response.ErrorMessage, fmt.Sprintf("... (%d bytes total)", len(largeBody)))
+		assert.Less(t, len(response.ErrorMessage), restclient.DefaultMaxLoggedBodyBytes+100)
+		assert.Equal(t, largeBody, response.Body, "the body itself is not truncated")
+	})
+
+	t.Run("Configured limit", func(t *testing.T) {
+		activities := NewRESTServiceActivitiesWithOptions(&testLogger{}, RESTServiceActivitiesOptions{MaxLoggedBodyBytes: 16})
+
+		response := invoke(t, activities, "/large")
+		assert.True(t, strings.HasSuffix(response.ErrorMessage, `: {"error":"xxxxxx... (10012 bytes total)`), response.ErrorMessage)
+	})
+
+	t.Run("Small body is kept whole", func(t *testing.T) {
+		response := invoke(t, NewRESTServiceActivities(&testLogger{}), "/small")
+		assert.True(t, strings.HasSuffix(response.ErrorMessage, `: {"error":"bad input"}`), response.ErrorMessage)
+		assert.NotContains(t, response.ErrorMessage, "bytes total")
+	})
+}
+
+func TestRESTServiceActivities_CaptureSentRequest(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte(`{"id":123}`))
+	}))
+	defer server.Close()
+
+	testSuite := &testsuite.WorkflowTestSuite{}
+	env := testSuite.NewTestActivityEnvironment()
+
+	activities := NewRESTServiceActivities(&testLogger{})
+	env.RegisterActivity(activities.InvokeRESTService)
+
+	t.Run("Bearer token is redacted", func(t *testing.T) {
+		request := RESTServiceRequest{
+			ServiceName: "UserService",
+			BaseURL:     server.URL,
+			Auth: restclient.AuthConfig{
+				Type:  restclient.BearerAuth,
+				Token: "super-secret-token",
+			},
+			Request: restclient.RESTRequest{
+				Method:      restclient.POST,
+				Endpoint:    "/users",
+				QueryParams: map[string]string{"dry_run": "true"},
+				Headers:     map[string]string{"X-Request-Source": "workflow"},
+				Body:        TestUser{Name: "Alice Johnson", Email: "alice@example.com"},
+			},
+			CaptureSentRequest: true,
+		}
+
+		val, err := env.ExecuteActivity(activities.InvokeRESTService, request)
+		require.NoError(t, err)
+
+		var response RESTServiceResponse
+		err = val.Get(&response)
+		require.NoError(t, err)
+		require.NotNil(t, response.SentRequest)
+
+		sent := response.SentRequest
+		assert.Equal(t, "POST", sent.Method)
+		assert.Equal(t, server.URL+"/users?dry_run=true", sent.URL)
+		assert.Equal(t, []string{redactedValue}, sent.Headers["Authorization"])
+		assert.Equal(t, []string{"workflow"}, sent.Headers["X-Request-Source"])
+		assert.Contains(t, sent.Body, "Alice Johnson")
+
+		captured, _ := json.Marshal(sent)
+		assert.NotContains(t, string(captured), "super-secret-token")
+	})
+
+	t.Run("API key in query is redacted", func(t *testing.T) {
+		request := RESTServiceRequest{
+			ServiceName: "UserService",
+			BaseURL:     server.URL,
+			Auth: restclient.AuthConfig{
+				Type:     restclient.APIKeyAuth,
+				APIKey:   "secret-api-key",
+				KeyQuery: "key",
+			},
+			Request: restclient.RESTRequest{
+				Method:   restclient.GET,
+				Endpoint: "/users/1",
+			},
+			CaptureSentRequest: true,
+		}
+
+		val, err := env.ExecuteActivity(activities.InvokeRESTService, request)
+		require.NoError(t, err)
+
+		var response RESTServiceResponse
+		err = val.Get(&response)
+		require.NoError(t, err)
+		require.NotNil(t, response.SentRequest)
+
+		assert.Equal(t, "GET", response.SentRequest.Method)
+		assert.Contains(t, response.SentRequest.URL, "/users/1")
+		assert.NotContains(t, response.SentRequest.URL, "secret-api-key")
+	})
+
+	t.Run("Not captured by default", func(t *testing.T) {
+		request := RESTServiceRequest{
+			ServiceName: "UserService",
+			BaseURL:     server.URL,
+			Auth:        restclient.AuthConfig{Type: restclient.NoAuth},
+			Request: restclient.RESTRequest{
+				Method:   restclient.GET,
+				Endpoint: "/users/1",
+			},
+		}
+
+		val, err := env.ExecuteActivity(activities.InvokeRESTService, request)
+		require.NoError(t, err)
+
+		var response RESTServiceResponse
+		err = val.Get(&response)
+		require.NoError(t, err)
+		assert.Nil(t, response.SentRequest)
+	})
+}
+
+func TestRESTServiceActivities_ClientCacheReusesOAuth2Token(t *testing.T) {
+	tokenFetches := 0
+	tokenServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		tokenFetches++
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"access_token": "cached-token",
+			"token_type":   "Bearer",
+			"expires_in":   3600,
+		})
+	}))
+	defer tokenServer.Close()
+
+	apiServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer cached-token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]string{"message": "authenticated"})
+	}))
+	defer apiServer.Close()
+
+	testSuite := &testsuite.WorkflowTestSuite{}
+	env := testSuite.NewTestActivityEnvironment()
+
+	cache := NewClientCache()
+	activities := NewRESTServiceActivitiesWithOptions(&testLogger{}, RESTServiceActivitiesOptions{ClientCache: cache})
+	env.RegisterActivity(activities.InvokeRESTService)
+
+	req := RESTServiceRequest{
+		ServiceName: "OAuthService",
+		BaseURL:     apiServer.URL,
+		Auth: restclient.AuthConfig{
+			Type:         restclient.OAuth2Auth,
+			ClientID:     "client-id",
+			ClientSecret: "client-secret",
+			TokenURL:     tokenServer.URL,
+		},
+		Request: restclient.RESTRequest{
+			Method:   restclient.GET,
+			Endpoint: "/resource",
+		},
+	}
+
+	invoke := func() {
+		val, err := env.ExecuteActivity(activities.InvokeRESTService, req)
+		require.NoError(t, err)
+
+		var resp RESTServiceResponse
+		require.NoError(t, val.Get(&resp))
+		assert.True(t, resp.Success)
+	}
+
+	for i := 0; i < 3; i++ {
+		invoke()
+	}
+	assert.Equal(t, 1, tokenFetches, "token should be fetched once and reused")
+
+	cache.Clear()
+	invoke()
+	assert.Equal(t, 2, tokenFetches, "clearing the cache should force a new token fetch")
+
+	// A different auth config gets its own client and token
+	req.Auth.Scopes = []string{"read"}
+	invoke()
+	assert.Equal(t, 3, tokenFetches)
+}
+
+func TestRESTServiceActivities_ClientCacheSkipsProviders(t *testing.T) {
+	var received []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received = append(received, r.Header.Get("Authorization"))
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	activities := NewRESTServiceActivities(&testLogger{})
+
+	tokenAuth := func(token string) restclient.AuthConfig {
+		return restclient.AuthConfig{
+			Type: restclient.BearerAuth,
+			TokenProvider: func(ctx context.Context) (string, error) {
+				return token, nil
+			},
+		}
+	}
+
+	first, err := activities.getClient(server.URL, tokenAuth("tenant-a"))
+	require.NoError(t, err)
+	second, err := activities.getClient(server.URL, tokenAuth("tenant-b"))
+	require.NoError(t, err)
+	assert.NotSame(t, first, second, "clients with providers must not be shared")
+
+	_, err = first.GET(context.Background(), "/resource", nil)
+	require.NoError(t, err)
+	_, err = second.GET(context.Background(), "/resource", nil)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"Bearer tenant-a", "Bearer tenant-b"}, received)
+
+	basicAuth := restclient.AuthConfig{
+		Type: restclient.BasicAuth,
+		CredentialProvider: func(ctx context.Context) (string, string, error) {
+			return "user", "secret", nil
+		},
+	}
+	third, err := activities.getClient(server.URL, basicAuth)
+	require.NoError(t, err)
+	fourth, err := activities.getClient(server.URL, basicAuth)
+	require.NoError(t, err)
+	assert.NotSame(t, third, fourth)
+}
+
+func TestRESTServiceActivities_InvokeRESTServiceWithRetry(t *testing.T) {
+	server := createTestServer(t)
+	defer server.Close()
+
+	testSuite := &testsuite.WorkflowTestSuite{}
+	env := testSuite.NewTestActivityEnvironment()
+
+	activities := NewRESTServiceActivities(&testLogger{})
+	env.RegisterActivity(activities.InvokeRESTService)
+	env.RegisterActivity(activities.InvokeRESTServiceWithRetry)
+
+	tests := []struct {
+		name          string
+		request       RESTServiceRequest
+		expectSuccess bool
+		expectedRetries int
+		checkResponse func(t *testing.T, resp *RESTServiceResponse)
+	}{
+		{
+			name: "Success after retries",
+			request: RESTServiceRequest{
+				ServiceName: "RetryService",
+				BaseURL:     server.URL,
+				Auth:        restclient.AuthConfig{Type: restclient.NoAuth},
+				Request: restclient.RESTRequest{
+					Method:   restclient.GET,
+					Endpoint: "/retry-test",
+				},
+				Retry: &RetryConfig{
+					MaxAttempts:          3,
+					InitialBackoff:       100 * time.Millisecond,
+					BackoffMultiplier:    2.0,
+					RetryableStatusCodes: []int{500},
+				},
+			},
+			expectSuccess: true,
+			expectedRetries: 2, // Failed 2 times, succeeded on 3rd
+			checkResponse: func(t *testing.T, resp *RESTServiceResponse) {
+				var response map[string]interface{}
+				err := json.Unmarshal([]byte(resp.Body), &response)
+				assert.NoError(t, err)
+				assert.Equal(t, "success after retry", response["message"])
+			},
+		},
+		{
+			name: "Non-retryable error",
+			request: RESTServiceRequest{
+				ServiceName: "BadRequestService",
+				BaseURL:     server.URL,
+				Auth:        restclient.AuthConfig{Type: restclient.NoAuth},
+				Request: restclient.RESTRequest{
+					Method:   restclient.GET,
+					Endpoint: "/error/400",
+				},
+				Retry: &RetryConfig{
+					MaxAttempts:          3,
+					InitialBackoff:       100 * time.Millisecond,
+					RetryableStatusCodes: []int{500, 429}, // 400 not in list
+				},
+			},
+			expectSuccess: false,
+			expectedRetries: 0, // Should not retry 400 errors
+			checkResponse: func(t *testing.T, resp *RESTServiceResponse) {
+				assert.Equal(t, 400, resp.StatusCode)
+				assert.Contains(t, resp.Body, "Bad Request")
+			},
+		},
+		{
+			name: "Max retries exceeded",
+			request: RESTServiceRequest{
+				ServiceName: "FailingService",
+				BaseURL:     server.URL,
+				Auth:        restclient.AuthConfig{Type: restclient.NoAuth},
+				Request: restclient.RESTRequest{
+					Method:   restclient.GET,
+					Endpoint: "/error/500",
+				},
+				Retry: &RetryConfig{
+					MaxAttempts:          2,
+					InitialBackoff:       50 * time.Millisecond,
+					RetryableStatusCodes: []int{500},
+				},
+			},
+			expectSuccess: false,
+			expectedRetries: 1, // 1 retry (2 total attempts)
+			checkResponse: func(t *testing.T, resp *RESTServiceResponse) {
+				assert.Equal(t, 500, resp.StatusCode)
+				assert.Contains(t, resp.ErrorMessage, "All 2 attempts failed")
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			val, err := env.ExecuteActivity(activities.InvokeRESTServiceWithRetry, tt.request)
+
+			assert.NoError(t, err)
+
+			var response RESTServiceResponse
+			err = val.Get(&response)
+			assert.NoError(t, err)
+
+			assert.Equal(t, tt.expectSuccess, response.Success)
+			assert.Equal(t, tt.expectedRetries, response.Retries)
+
+			if tt.checkResponse != nil {
+				tt.checkResponse(t, &response)
+			}
+		})
+	}
+}
+
+
+func TestRESTServiceActivities_InvokeRESTServiceWithRetry_OverallTimeout(t *testing.T) {
+	attempts := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	testSuite := &testsuite.WorkflowTestSuite{}
+	env := testSuite.NewTestActivityEnvironment()
+
+	activities := NewRESTServiceActivities(&testLogger{})
+	env.RegisterActivity(activities.InvokeRESTServiceWithRetry)
+
+	req := RESTServiceRequest{
+		ServiceName: "UnavailableService",
+		BaseURL:     server.URL,
+		Auth:        restclient.AuthConfig{Type: restclient.NoAuth},
+		Request: restclient.RESTRequest{
+			Method:   restclient.GET,
+			Endpoint: "/unavailable",
+		},
+		Timeout: 30 * time.Second, // Per-attempt timeout never triggers
+		Retry: &RetryConfig{
+			MaxAttempts:          5,
+			InitialBackoff:       500 * time.Millisecond,
+			BackoffMultiplier:    2.0,
+			RetryableStatusCodes: []int{503},
+		},
+		OverallTimeout: 800 * time.Millisecond, // Expires during the second backoff
+	}
+
+	start := time.Now()
+	_, err := env.ExecuteActivity(activities.InvokeRESTServiceWithRetry, req)
+	elapsed := time.Since(start)
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "overall timeout")
+	assert.Equal(t, 2, attempts, "retries should stop once the overall timeout expires")
+	assert.Less(t, elapsed, 1500*time.Millisecond)
+}
+
+func TestRESTServiceActivities_InvokeRESTServiceWithRetry_TotalTimeout(t *testing.T) {
+	attempts := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	testSuite := &testsuite.WorkflowTestSuite{}
+	env := testSuite.NewTestActivityEnvironment()
+
+	activities := NewRESTServiceActivities(&testLogger{})
+	env.RegisterActivity(activities.InvokeRESTServiceWithRetry)
+
+	req := RESTServiceRequest{
+		ServiceName: "UnavailableService",
+		BaseURL:     server.URL,
+		Auth:        restclient.AuthConfig{Type: restclient.NoAuth},
+		Request: restclient.RESTRequest{
+			Method:   restclient.GET,
+			Endpoint: "/unavailable",
+		},
+		Retry: &RetryConfig{
+			MaxAttempts:          4,
+			InitialBackoff:       200 * time.Millisecond,
+			BackoffMultiplier:    2.0,
+			RetryableStatusCodes: []int{503},
+			TotalTimeout:         500 * time.Millisecond, // Second backoff (400ms) would exceed the budget
+		},
+	}
+
+	start := time.Now()
+	_, err := env.ExecuteActivity(activities.InvokeRESTServiceWithRetry, req)
+	elapsed := time.Since(start)
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "retry total timeout")
+	assert.Equal(t, 2, attempts, "retries should stop before exhausting attempts")
+	assert.Less(t, elapsed, 500*time.Millisecond)
+}
+func TestRESTServiceActivities_InvokeRESTServiceWithRetry_RetryAfterExceedsMaxBackoff(t *testing.T) {
+	attempts := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		w.Header().Set("Retry-After", "120")
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	testSuite := &testsuite.WorkflowTestSuite{}
+	env := testSuite.NewTestActivityEnvironment()
+
+	activities := NewRESTServiceActivities(&testLogger{})
+	env.RegisterActivity(activities.InvokeRESTServiceWithRetry)
+
+	req := RESTServiceRequest{
+		ServiceName: "ThrottledService",
+		BaseURL:     server.URL,
+		Auth:        restclient.AuthConfig{Type: restclient.NoAuth},
+		Request: restclient.RESTRequest{
+			Method:   restclient.GET,
+			Endpoint: "/throttled",
+		},
+		Retry: &RetryConfig{
+			MaxAttempts:          3,
+			InitialBackoff:       10 * time.Millisecond,
+			BackoffMultiplier:    2.0,
+			MaxBackoff:           time.Second,
+			RetryableStatusCodes: []int{503},
+		},
+	}
+
+	start := time.Now()
+	_, err := env.ExecuteActivity(activities.InvokeRESTServiceWithRetry, req)
+	elapsed := time.Since(start)
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "Retry-After 2m0s")
+	assert.Equal(t, 1, attempts, "should not retry before the server's Retry-After")
+	assert.Less(t, elapsed, time.Second)
+}
+
+func TestRESTServiceActivities_InvokeRESTServiceWithRetry_MaxAttemptsClamp(t *testing.T) {
+	var attempts int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&attempts, 1)
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		name             string
+		maxAttempts      int
+		cap              int
+		expectedAttempts int32
+	}{
+		{name: "Zero runs once", maxAttempts: 0, expectedAttempts: 1},
+		{name: "Negative runs once", maxAttempts: -5, expectedAttempts: 1},
+		{name: "Within range is kept", maxAttempts: 3, expectedAttempts: 3},
+		{name: "Over default cap", maxAttempts: 1000, expectedAttempts: DefaultMaxAttemptsCap},
+		{name: "Over custom cap", maxAttempts: 1000, cap: 4, expectedAttempts: 4},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			atomic.StoreInt32(&attempts, 0)
+
+			testSuite := &testsuite.WorkflowTestSuite{}
+			env := testSuite.NewTestActivityEnvironment()
+
+			activities := NewRESTServiceActivitiesWithOptions(&testLogger{}, RESTServiceActivitiesOptions{MaxAttemptsCap: tt.cap})
+			env.RegisterActivity(activities.InvokeRESTServiceWithRetry)
+
+			req := RESTServiceRequest{
+				ServiceName: "FailingService",
+				BaseURL:     server.URL,
+				Auth:        restclient.AuthConfig{Type: restclient.NoAuth},
+				Request: restclient.RESTRequest{
+					Method:   restclient.GET,
+					Endpoint: "/fail",
+				},
+				Retry: &RetryConfig{
+					MaxAttempts:    tt.maxAttempts,
+					InitialBackoff: time.Millisecond,
+					MaxBackoff:     time.Millisecond,
+				},
+			}
+
+			_, err := env.ExecuteActivity(activities.InvokeRESTServiceWithRetry, req)
+			require.Error(t, err)
+			assert.Equal(t, tt.expectedAttempts, atomic.LoadInt32(&attempts))
+		})
+	}
+}
+
+func TestRESTServiceActivities_InvokeRESTServiceWithRetry_AttemptsLog(t *testing.T) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&calls, 1) <= 2 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			w.Write([]byte("try again"))
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"status":"ok"}`))
+	}))
+	defer server.Close()
+
+	testSuite := &testsuite.WorkflowTestSuite{}
+	env := testSuite.NewTestActivityEnvironment()
+
+	activities := NewRESTServiceActivities(&testLogger{})
+	env.RegisterActivity(activities.InvokeRESTServiceWithRetry)
+
+	req := RESTServiceRequest{
+		ServiceName: "FlakyService",
+		BaseURL:     server.URL,
+		Auth:        restclient.AuthConfig{Type: restclient.NoAuth},
+		Request: restclient.RESTRequest{
+			Method:   restclient.GET,
+			Endpoint: "/flaky",
+		},
+		Retry: &RetryConfig{
+			MaxAttempts:    3,
+			InitialBackoff: time.Millisecond,
+			MaxBackoff:     time.Millisecond,
+		},
+	}
+
+	val, err := env.ExecuteActivity(activities.InvokeRESTServiceWithRetry, req)
+	require.NoError(t, err)
+
+	var response RESTServiceResponse
+	require.NoError(t, val.Get(&response))
+	assert.True(t, response.Success)
+	assert.Equal(t, 2, response.Retries)
+
+	require.Len(t, response.AttemptsLog, 3)
+	for i, record := range response.AttemptsLog {
+		assert.Equal(t, i+1, record.Attempt)
+		assert.Greater(t, record.Duration, time.Duration(0))
+	}
+	for _, record := range response.AttemptsLog[:2] {
+		assert.Equal(t, http.StatusServiceUnavailable, record.StatusCode)
+		assert.Contains(t, record.Error, "try again")
+	}
+	assert.Equal(t, http.StatusOK, response.AttemptsLog[2].StatusCode)
+	assert.Empty(t, response.AttemptsLog[2].Error)
+}
+
+// flakyTransport fails the first failures round trips with err before delegating
+type flakyTransport struct {
+	failures int32
+	err      error
+	calls    int32
+	next     http.RoundTripper
+}
+
+func (t *flakyTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if atomic.AddInt32(&t.calls, 1) <= t.failures {
+		return nil, t.err
+	}
+	return t.next.RoundTrip(req)
+}
+
+func TestRESTServiceActivities_InvokeRESTServiceWithRetry_RetryableErrorSubstrings(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(`{"status":"ok"}`))
+	}))
+	defer server.Close()
+
+	auth := restclient.AuthConfig{Type: restclient.NoAuth}
+	newRequest := func() RESTServiceRequest {
+		return RESTServiceRequest{
+			ServiceName: "FlakyService",
+			BaseURL:     server.URL,
+			Auth:        auth,
+			Request: restclient.RESTRequest{
+				Method:   restclient.GET,
+				Endpoint: "/flaky",
+			},
+			Retry: &RetryConfig{
+				MaxAttempts:              3,
+				InitialBackoff:           10 * time.Millisecond,
+				RetryableErrorSubstrings: []string{"connection reset"},
+			},
+		}
+	}
+
+	// Install the flaky transport on the cached client the activity will use
+	setup := func(t *testing.T, transportErr error) (*RESTServiceActivities, *flakyTransport) {
+		activities := NewRESTServiceActivities(&testLogger{})
+		client, err := activities.getClient(server.URL, auth)
+		require.NoError(t, err)
+		transport := &flakyTransport{failures: 2, err: transportErr, next: http.DefaultTransport}
+		client.HTTPClient().Transport = transport
+		return activities, transport
+	}
+
+	t.Run("retries matching errors", func(t *testing.T) {
+		activities, transport := setup(t, errors.New("read tcp 127.0.0.1:1234: connection reset by peer"))
+
+		testSuite := &testsuite.WorkflowTestSuite{}
+		env := testSuite.NewTestActivityEnvironment()
+		env.RegisterActivity(activities.InvokeRESTServiceWithRetry)
+
+		val, err := env.ExecuteActivity(activities.InvokeRESTServiceWithRetry, newRequest())
+		require.NoError(t, err)
+
+		var result RESTServiceResponse
+		require.NoError(t, val.Get(&result))
+		assert.True(t, result.Success)
+		assert.Equal(t, 2, result.Retries)
+		assert.Equal(t, int32(3), atomic.LoadInt32(&transport.calls))
+	})
+
+	t.Run("stops on other errors", func(t *testing.T) {
+		activities, transport := setup(t, errors.New("tls: bad certificate"))
+
+		testSuite := &testsuite.WorkflowTestSuite{}
+		env := testSuite.NewTestActivityEnvironment()
+		env.RegisterActivity(activities.InvokeRESTServiceWithRetry)
+
+		_, err := env.ExecuteActivity(activities.InvokeRESTServiceWithRetry, newRequest())
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "bad certificate")
+		assert.Equal(t, int32(1), atomic.LoadInt32(&transport.calls))
+	})
+}
+
+func TestRESTServiceActivities_Metrics(t *testing.T) {
+	server := createTestServer(t)
+	defer server.Close()
+
+	closedServer := httptest.NewServer(http.NotFoundHandler())
+	closedServer.Close()
+
+	testSuite := &testsuite.WorkflowTestSuite{}
+	env := testSuite.NewTestActivityEnvironment()
+
+	metrics := NewMetrics()
+	activities := NewRESTServiceActivitiesWithOptions(&testLogger{}, RESTServiceActivitiesOptions{Metrics: metrics})
+	env.RegisterActivity(activities.InvokeRESTService)
+	env.RegisterActivity(activities.InvokeRESTServiceWithRetry)
+
+	newRequest := func(baseURL, endpoint string) RESTServiceRequest {
+		return RESTServiceRequest{
+			ServiceName: "UserService",
+			BaseURL:     baseURL,
+			Auth:        restclient.AuthConfig{Type: restclient.NoAuth},
+			Request: restclient.RESTRequest{
+				Method:   restclient.GET,
+				Endpoint: endpoint,
+			},
+		}
+	}
+
+	_, err := env.ExecuteActivity(activities.InvokeRESTService, newRequest(server.URL, "/users/1"))
+	require.NoError(t, err)
+	_, err = env.ExecuteActivity(activities.InvokeRESTService, newRequest(server.URL, "/users/1"))
+	require.NoError(t, err)
+	_, err = env.ExecuteActivity(activities.InvokeRESTService, newRequest(server.URL, "/error/400"))
+	require.NoError(t, err)
+	_, err = env.ExecuteActivity(activities.InvokeRESTService, newRequest(closedServer.URL, "/users/1"))
+	require.Error(t, err)
+
+	retryReq := newRequest(server.URL, "/error/500")
+	retryReq.Retry = &RetryConfig{
+		MaxAttempts:    3,
+		InitialBackoff: 10 * time.Millisecond,
+	}
+	_, err = env.ExecuteActivity(activities.InvokeRESTServiceWithRetry, retryReq)
+	require.Error(t, err)
+
+	snapshot := metrics.Snapshot()
+	assert.Equal(t, int64(7), snapshot.TotalRequests)
+	assert.Equal(t, int64(1), snapshot.Errors)
+	assert.Equal(t, int64(2), snapshot.Retries)
+	assert.Equal(t, map[int]int64{200: 2, 400: 1, 500: 3}, snapshot.StatusCodes)
+	assert.Equal(t, int64(6), snapshot.Latency.Count)
+	assert.Greater(t, snapshot.Latency.Sum, time.Duration(0))
+
+	metrics.Reset()
+	assert.Equal(t, int64(0), metrics.Snapshot().TotalRequests)
+}
+
+func TestMetrics_LatencyHistogram(t *testing.T) {
+	metrics := NewMetrics(100*time.Millisecond, 10*time.Millisecond, 50*time.Millisecond)
+
+	metrics.recordResponse(200, 5*time.Millisecond)
+	metrics.recordResponse(200, 10*time.Millisecond) // Bounds are inclusive
+	metrics.recordResponse(200, 30*time.Millisecond)
+	metrics.recordResponse(503, 2*time.Second)
+
+	latency := metrics.Snapshot().Latency
+	assert.Equal(t, []HistogramBucket{
+		{UpperBound: 10 * time.Millisecond, Count: 2},
+		{UpperBound: 50 * time.Millisecond, Count: 3},
+		{UpperBound: 100 * time.Millisecond, Count: 3},
+	}, latency.Buckets)
+	assert.Equal(t, int64(4), latency.Count)
+	assert.Equal(t, 2045*time.Millisecond, latency.Sum)
+}
+
+func TestBackoffDuration(t *testing.T) {
+	ms := time.Millisecond
+
+	tests := []struct {
+		name        string
+		backoffFunc string
+		maxBackoff  time.Duration
+		expected    []time.Duration
+	}{
+		{
+			name:        "Constant",
+			backoffFunc: ConstantBackoff,
+			expected:    []time.Duration{100 * ms, 100 * ms, 100 * ms, 100 * ms, 100 * ms},
+		},
+		{
+			name:        "Linear",
+			backoffFunc: LinearBackoff,
+			expected:    []time.Duration{100 * ms, 200 * ms, 300 * ms, 400 * ms, 500 * ms},
+		},
+		{
+			name:        "Exponential",
+			backoffFunc: ExponentialBackoff,
+			expected:    []time.Duration{100 * ms, 200 * ms, 400 * ms, 800 * ms, 1600 * ms},
+		},
+		{
+			name:        "Fibonacci",
+			backoffFunc: FibonacciBackoff,
+			expected:    []time.Duration{100 * ms, 100 * ms, 200 * ms, 300 * ms, 500 * ms},
+		},
+		{
+			name:        "Capped at max backoff",
+			backoffFunc: ExponentialBackoff,
+			maxBackoff:  500 * ms,
+			expected:    []time.Duration{100 * ms, 200 * ms, 400 * ms, 500 * ms, 500 * ms},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := &RetryConfig{
+				InitialBackoff:    100 * ms,
+				BackoffMultiplier: 2.0,
+				MaxBackoff:        tt.maxBackoff,
+				BackoffFunc:       tt.backoffFunc,
+			}
+
+			delays := make([]time.Duration, len(tt.expected))
+			for i := range delays {
+				delays[i] = backoffDuration(config, i+1)
+			}
+			assert.Equal(t, tt.expected, delays)
+		})
+	}
+
+	t.Run("Unknown backoff function", func(t *testing.T) {
+		assert.True(t, isKnownBackoffFunc(FibonacciBackoff))
+		assert.False(t, isKnownBackoffFunc("quadratic"))
+	})
+}
+
+func TestRetryAfterWait(t *testing.T) {
+	rng := rand.New(rand.NewSource(42))
+
+	retryAfter := 2 * time.Second
+	jitter := 0.25
+	maxWait := retryAfter + time.Duration(jitter*float64(retryAfter))
+
+	waits := make(map[time.Duration]bool)
+	for i := 0; i < 100; i++ {
+		wait := retryAfterWait(retryAfter, jitter, 0, rng)
+
+		assert.GreaterOrEqual(t, wait, retryAfter, "Should never retry before Retry-After")
+		assert.LessOrEqual(t, wait, maxWait, "Should stay within the jitter bound")
+		waits[wait] = true
+	}
+	assert.Greater(t, len(waits), 1, "Jitter should spread out retries")
+
+	t.Run("No jitter configured", func(t *testing.T) {
+		assert.Equal(t, retryAfter, retryAfterWait(retryAfter, 0, 0, rng))
+	})
+
+	t.Run("Jitter capped at max wait", func(t *testing.T) {
+		for i := 0; i < 100; i++ {
+			wait := retryAfterWait(28*time.Second, jitter, 30*time.Second, rng)
+			assert.GreaterOrEqual(t, wait, 28*time.Second)
+			assert.LessOrEqual(t, wait, 30*time.Second)
+		}
+	})
+
+	t.Run("Never shorter than Retry-After", func(t *testing.T) {
+		assert.Equal(t, time.Hour, retryAfterWait(time.Hour, jitter, 30*time.Second, rng))
+	})
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	tests := []struct {
+		name     string
+		headers  map[string][]string
+		expected time.Duration
+		ok       bool
+	}{
+		{
+			name:     "Delay seconds",
+			headers:  map[string][]string{"Retry-After": {"5"}},
+			expected: 5 * time.Second,
+			ok:       true,
+		},
+		{
+			name:     "HTTP date in the past",
+			headers:  map[string][]string{"Retry-After": {"Wed, 21 Oct 2015 07:28:00 GMT"}},
+			expected: 0,
+			ok:       true,
+		},
+		{
+			name: "Missing header",
+			ok:   false,
+		},
+		{
+			name:    "Invalid value",
+			headers: map[string][]string{"Retry-After": {"soon"}},
+			ok:      false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			delay, ok := parseRetryAfter(tt.headers)
+
+			assert.Equal(t, tt.ok, ok)
+			assert.Equal(t, tt.expected, delay)
+		})
+	}
+}
+
+func TestRESTServiceActivities_CRUDOperations(t *testing.T) {
+	server := createTestServer(t)
+	defer server.Close()
+
+	testSuite := &testsuite.WorkflowTestSuite{}
+	env := testSuite.NewTestActivityEnvironment()
+
+	activities := NewRESTServiceActivities(&testLogger{})
+	env.RegisterActivity(activities.GetResource)
+	env.RegisterActivity(activities.CreateResource)
+	env.RegisterActivity(activities.UpdateResource)
+	env.RegisterActivity(activities.DeleteResource)
+
+	auth := restclient.AuthConfig{Type: restclient.NoAuth}
+
+	t.Run("GetResource", func(t *testing.T) {
+		val, err := env.ExecuteActivity(
+			activities.GetResource,
+			"UserService",
+			server.URL,
+			"/users/1",
+			auth,
+			nil,
+		)
+
+		assert.NoError(t, err)
+
+		var response RESTServiceResponse
+		err = val.Get(&response)
+		assert.NoError(t, err)
+
+		assert.True(t, response.Success)
+		assert.Equal(t, 200, response.StatusCode)
+
+		var user TestUser
+		err = json.Unmarshal([]byte(response.Body), &user)
+		assert.NoError(t, err)
+		assert.Equal(t, 1, user.ID)
+	})
+
+	t.Run("CreateResource", func(t *testing.T) {
+		newUser := TestUser{
+			Name:  "Bob Wilson",
+			Email: "bob@example.com",
+		}
+
+		val, err := env.ExecuteActivity(
+			activities.CreateResource,
+			"UserService",
+			server.URL,
+			"/users",
+			auth,
+			newUser,
+		)
+
+		assert.NoError(t, err)
+
+		var response RESTServiceResponse
+		err = val.Get(&response)
+		assert.NoError(t, err)
+
+		assert.True(t, response.Success)
+		assert.Equal(t, 201, response.StatusCode)
+
+		var createdUser TestUser
+		err = json.Unmarshal([]byte(response.Body), &createdUser)
+		assert.NoError(t, err)
+		assert.Equal(t, 123, createdUser.ID)
+		assert.Equal(t, "Bob Wilson", createdUser.Name)
+	})
+
+	t.Run("UpdateResource", func(t *testing.T) {
+		updateUser := TestUser{
+			Name:  "John Updated",
+			Email: "john.updated@example.com",
+		}
+
+		val, err := env.ExecuteActivity(
+			activities.UpdateResource,
+			"UserService",
+			server.URL,
+			"/users/1",
+			auth,
+			updateUser,
+		)
+
+		assert.NoError(t, err)
+
+		var response RESTServiceResponse
+		err = val.Get(&response)
+		assert.NoError(t, err)
+
+		assert.True(t, response.Success)
+		assert.Equal(t, 200, response.StatusCode)
+
+		var updatedUser TestUser
+		err = json.Unmarshal([]byte(response.Body), &updatedUser)
+		assert.NoError(t, err)
+		assert.Equal(t, 1, updatedUser.ID)
+		assert.Equal(t, "John Updated", updatedUser.Name)
+	})
+
+	t.Run("DeleteResource", func(t *testing.T) {
+		val, err := env.ExecuteActivity(
+			activities.DeleteResource,
+			"UserService",
+			server.URL,
+			"/users/1",
+			auth,
+		)
+
+		assert.NoError(t, err)
+
+		var response RESTServiceResponse
+		err = val.Get(&response)
+		assert.NoError(t, err)
+
+		assert.True(t, response.Success)
+		assert.Equal(t, 204, response.StatusCode)
+	})
+}
+
+func TestGetTyped(t *testing.T) {
+	server := createTestServer(t)
+	defer server.Close()
+
+	testSuite := &testsuite.WorkflowTestSuite{}
+	env := testSuite.NewTestActivityEnvironment()
+
+	activities := NewRESTServiceActivities(&testLogger{})
+	auth := restclient.AuthConfig{Type: restclient.NoAuth}
+
+	// GetTyped is generic, so wrap it in an activity to run it in an activity context
+	getUser := func(ctx context.Context, endpoint string) (*RESTServiceResponse, error) {
+		user, resp, err := GetTyped[TestUser](activities, ctx, "UserService", server.URL, endpoint, auth)
+		if err != nil {
+			return resp, err
+		}
+		assert.Equal(t, TestUser{ID: 1, Name: "John Doe", Email: "john@example.com"}, user)
+		return resp, nil
+	}
+	deleteUser := func(ctx context.Context, endpoint string) (*RESTServiceResponse, error) {
+		result, resp, err := DeleteTyped[map[string]interface{}](activities, ctx, "UserService", server.URL, endpoint, auth)
+		assert.Nil(t, result)
+		return resp, err
+	}
+	env.RegisterActivityWithOptions(getUser, activity.RegisterOptions{Name: "GetUser"})
+	env.RegisterActivityWithOptions(deleteUser, activity.RegisterOptions{Name: "DeleteUser"})
+
+	t.Run("Decodes successful response", func(t *testing.T) {
+		val, err := env.ExecuteActivity("GetUser", "/users/1")
+		require.NoError(t, err)
+
+		var response RESTServiceResponse
+		require.NoError(t, val.Get(&response))
+		assert.Equal(t, 200, response.StatusCode)
+	})
+
+	t.Run("Non-success status", func(t *testing.T) {
+		_, err := env.ExecuteActivity("GetUser", "/error/400")
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "HTTP 400")
+	})
+
+	t.Run("Empty body leaves zero value", func(t *testing.T) {
+		val, err := env.ExecuteActivity("DeleteUser", "/users/1")
+		require.NoError(t, err)
+
+		var response RESTServiceResponse
+		require.NoError(t, val.Get(&response))
+		assert.Equal(t, 204, response.StatusCode)
+	})
+}
+
+func TestCollectAllPages(t *testing.T) {
+	pages := map[string]string{
+		"1": `{"users":[{"id":1,"name":"John Doe","email":"john@example.com"},{"id":2,"name":"Jane Smith","email":"jane@example.com"}]}`,
+		"2": `{"users":[{"id":3,"name":"Bob Johnson","email":"bob@example.com"}]}`,
+		"3": `{"users":[]}`,
+	}
+	cursorPages := map[string]string{
+		"":    `{"users":[{"id":1,"name":"John Doe","email":"john@example.com"}],"next_cursor":"abc"}`,
+		"abc": `{"users":[{"id":2,"name":"Jane Smith","email":"jane@example.com"}],"next_cursor":""}`,
+	}
+
+	var requested []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requested = append(requested, r.URL.RawQuery)
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/users":
+			w.Write([]byte(pages[r.URL.Query().Get("page")]))
+		case "/users/cursor":
+			w.Write([]byte(cursorPages[r.URL.Query().Get("cursor")]))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	testSuite := &testsuite.WorkflowTestSuite{}
+	env := testSuite.NewTestActivityEnvironment()
+
+	activities := NewRESTServiceActivities(&testLogger{})
+
+	// CollectAllPages is generic, so wrap it in an activity to run it in an activity context
+	collectUsers := func(ctx context.Context, endpoint string, maxPages int) ([]TestUser, error) {
+		return CollectAllPages[TestUser](ctx, activities, RESTServiceRequest{
+			ServiceName: "UserService",
+			BaseURL:     server.URL,
+			Auth:        restclient.AuthConfig{Type: restclient.NoAuth},
+			Request: restclient.RESTRequest{
+				Method:      restclient.GET,
+				Endpoint:    endpoint,
+				QueryParams: map[string]string{"limit": "2"},
+			},
+		}, "users", maxPages)
+	}
+	env.RegisterActivityWithOptions(collectUsers, activity.RegisterOptions{Name: "CollectUsers"})
+
+	t.Run("Page numbers", func(t *testing.T) {
+		requested = nil
+		val, err := env.ExecuteActivity("CollectUsers", "/users", 10)
+		require.NoError(t, err)
+
+		var users []TestUser
+		require.NoError(t, val.Get(&users))
+		assert.Equal(t, []TestUser{
+			{ID: 1, Name: "John Doe", Email: "john@example.com"},
+			{ID: 2, Name: "Jane Smith", Email: "jane@example.com"},
+			{ID: 3, Name: "Bob Johnson", Email: "bob@example.com"},
+		}, users)
+		assert.Equal(t, []string{"limit=2&page=1", "limit=2&page=2", "limit=2&page=3"}, requested)
+	})
+
+	t.Run("Cursor", func(t *testing.T) {
+		requested = nil
+		val, err := env.ExecuteActivity("CollectUsers", "/users/cursor", 10)
+		require.NoError(t, err)
+
+		var users []TestUser
+		require.NoError(t, val.Get(&users))
+		assert.Equal(t, []TestUser{
+			{ID: 1, Name: "John Doe", Email: "john@example.com"},
+			{ID: 2, Name: "Jane Smith", Email: "jane@example.com"},
+		}, users)
+		assert.Equal(t, []string{"limit=2&page=1", "cursor=abc&limit=2"}, requested)
+	})
+
+	t.Run("More pages than maxPages", func(t *testing.T) {
+		_, err := env.ExecuteActivity("CollectUsers", "/users", 1)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "has more pages after 1 pages")
+	})
+
+	t.Run("Failed page", func(t *testing.T) {
+		_, err := env.ExecuteActivity("CollectUsers", "/missing", 10)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "HTTP 404")
+	})
+}
+
+func TestRESTServiceActivities_DeleteResourceWithBody(t *testing.T) {
+	var receivedMethod string
+	var receivedBody map[string][]int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		receivedMethod = r.Method
+		json.NewDecoder(r.Body).Decode(&receivedBody)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	testSuite := &testsuite.WorkflowTestSuite{}
+	env := testSuite.NewTestActivityEnvironment()
+
+	activities := NewRESTServiceActivities(&testLogger{})
+	env.RegisterActivity(activities.DeleteResourceWithBody)
+
+	val, err := env.ExecuteActivity(
+		activities.DeleteResourceWithBody,
+		"SearchService",
+		server.URL,
+		"/index/_bulk_delete",
+		restclient.AuthConfig{Type: restclient.NoAuth},
+		map[string][]int{"ids": {1, 2, 3}},
+	)
+
+	assert.NoError(t, err)
+
+	var response RESTServiceResponse
+	err = val.Get(&response)
+	assert.NoError(t, err)
+
+	assert.True(t, response.Success)
+	assert.Equal(t, 204, response.StatusCode)
+	assert.Equal(t, "DELETE", receivedMethod)
+	assert.Equal(t, []int{1, 2, 3}, receivedBody["ids"])
+}
+
+func TestRESTServiceActivities_CreateIfNotExists(t *testing.T) {
+	var (
+		mu       sync.Mutex
+		users    = map[string]string{"/users/1": `{"id":1,"name":"Existing"}`}
+		requests []string
+		conflict bool
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		requests = append(requests, r.Method+" "+r.URL.Path)
+
+		switch r.Method {
+		case http.MethodGet:
+			user, ok := users[r.URL.Path]
+			if !ok {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			w.Header().Set("Content-Type", "application/json")
+			w.Write([]byte(user))
+		case http.MethodPost:
+			var user TestUser
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&user))
+			stored := fmt.Sprintf(`{"id":%d,"name":%q}`, user.ID, user.Name)
+			users[fmt.Sprintf("/users/%d", user.ID)] = stored
+			if conflict {
+				w.WriteHeader(http.StatusConflict)
+				return
+			}
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusCreated)
+			w.Write([]byte(stored))
+		}
+	}))
+	defer server.Close()
+
+	testSuite := &testsuite.WorkflowTestSuite{}
+	env := testSuite.NewTestActivityEnvironment()
+
+	activities := NewRESTServiceActivities(&testLogger{})
+	env.RegisterActivity(activities.CreateIfNotExists)
+
+	auth := restclient.AuthConfig{Type: restclient.NoAuth}
+
+	run := func(t *testing.T, getEndpoint string, body TestUser) CreateIfNotExistsResponse {
+		mu.Lock()
+		requests = nil
+		mu.Unlock()
+
+		val, err := env.ExecuteActivity(activities.CreateIfNotExists, "UserService", server.URL, getEndpoint, "/users", auth, body)
+		require.NoError(t, err)
+
+		var response CreateIfNotExistsResponse
+		require.NoError(t, val.Get(&response))
+		require.NotNil(t, response.Response)
+		return response
+	}
+
+	t.Run("Already exists", func(t *testing.T) {
+		response := run(t, "/users/1", TestUser{ID: 1, Name: "Replacement"})
+
+		assert.False(t, response.Created)
+		assert.Equal(t, 200, response.Response.StatusCode)
+		assert.JSONEq(t, `{"id":1,"name":"Existing"}`, response.Response.Body)
+		assert.Equal(t, []string{"GET /users/1"}, requests)
+	})
+
+	t.Run("Created", func(t *testing.T) {
+		response := run(t, "/users/2", TestUser{ID: 2, Name: "New"})
+
+		assert.True(t, response.Created)
+		assert.Equal(t, 201, response.Response.StatusCode)
+		assert.JSONEq(t, `{"id":2,"name":"New"}`, response.Response.Body)
+		assert.Equal(t, []string{"GET /users/2", "POST /users"}, requests)
+	})
+
+	t.Run("Created concurrently", func(t *testing.T) {
+		conflict = true
+		defer func() { conflict = false }()
+
+		response := run(t, "/users/3", TestUser{ID: 3, Name: "Racing"})
+
+		assert.False(t, response.Created)
+		assert.Equal(t, 200, response.Response.StatusCode)
+		assert.JSONEq(t, `{"id":3,"name":"Racing"}`, response.Response.Body)
+		assert.Equal(t, []string{"GET /users/3", "POST /users", "GET /users/3"}, requests)
+	})
+}
+
+func TestRESTServiceActivities_PatchResourcePartial(t *testing.T) {
+	type address struct {
+		City    string `json:"city"`
+		Country string `json:"country"`
+	}
+	type userUpdate struct {
+		Name    string   `json:"name"`
+		Email   string   `json:"email"`
+		Age     int      `json:"age"`
+		Active  bool     `json:"active"`
+		Tags    []string `json:"tags"`
+		Address address  `json:"address"`
+		Manager *address `json:"manager"`
+	}
+
+	var receivedMethod, receivedContentType string
+	var receivedBody string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		receivedMethod = r.Method
+		receivedContentType = r.Header.Get("Content-Type")
+		body, _ := io.ReadAll(r.Body)
+		receivedBody = string(body)
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(`{"id":1}`))
+	}))
+	defer server.Close()
+
+	testSuite := &testsuite.WorkflowTestSuite{}
+	env := testSuite.NewTestActivityEnvironment()
+
+	activities := NewRESTServiceActivities(&testLogger{})
+	env.RegisterActivity(activities.PatchResourcePartial)
+
+	auth := restclient.AuthConfig{Type: restclient.NoAuth}
+
+	t.Run("Only set fields are sent", func(t *testing.T) {
+		val, err := env.ExecuteActivity(activities.PatchResourcePartial, "UserService", server.URL, "/users/1", auth,
+			userUpdate{Email: "new@example.com", Address: address{City: "Berlin"}})
+		require.NoError(t, err)
+
+		var response RESTServiceResponse
+		require.NoError(t, val.Get(&response))
+		assert.True(t, response.Success)
+
+		assert.Equal(t, "PATCH", receivedMethod)
+		assert.Equal(t, "application/merge-patch+json", receivedContentType)
+		assert.JSONEq(t, `{"email":"new@example.com","address":{"city":"Berlin"}}`, receivedBody)
+	})
+
+	t.Run("Body that is not an object", func(t *testing.T) {
+		_, err := env.ExecuteActivity(activities.PatchResourcePartial, "UserService", server.URL, "/users/1", auth, []int{1, 2})
+		require.Error(t, err)
+
+		var appErr *temporal.ApplicationError
+		require.True(t, errors.As(err, &appErr))
+		assert.True(t, appErr.NonRetryable())
+	})
+}
+
+func TestSparsePatch(t *testing.T) {
+	patch, err := sparsePatch(map[string]interface{}{
+		"name":   "",
+		"count":  0,
+		"ratio":  0.5,
+		"flag":   false,
+		"items":  []string{},
+		"nested": map[string]interface{}{"empty": map[string]interface{}{"zero": 0}},
+		"kept":   map[string]interface{}{"value": true, "none": nil},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{
+		"ratio": 0.5,
+		"kept":  map[string]interface{}{"value": true},
+	}, patch)
+}
+
+func TestRESTServiceActivities_UploadStream(t *testing.T) {
+	content := bytes.Repeat([]byte("report-line-0123456789\n"), 10000)
+	filePath := filepath.Join(t.TempDir(), "report.csv")
+	require.NoError(t, os.WriteFile(filePath, content, 0644))
+
+	var received []byte
+	var receivedContentType string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "PUT", r.Method)
+		receivedContentType = r.Header.Get("Content-Type")
+		received, _ = io.ReadAll(r.Body)
+		w.WriteHeader(http.StatusCreated)
+	}))
+	defer server.Close()
+
+	testSuite := &testsuite.WorkflowTestSuite{}
+	env := testSuite.NewTestActivityEnvironment()
+
+	var heartbeats []int64
+	env.SetOnActivityHeartbeatListener(func(activityInfo *activity.Info, details converter.EncodedValues) {
+		var sent int64
+		if err := details.Get(&sent); err == nil {
+			heartbeats = append(heartbeats, sent)
+		}
+	})
+
+	activities := NewRESTServiceActivities(&testLogger{})
+	env.RegisterActivity(activities.UploadStream)
+
+	val, err := env.ExecuteActivity(activities.UploadStream,
+		"ReportService", server.URL, "/reports/daily",
+		restclient.AuthConfig{Type: restclient.NoAuth},
+		filePath, "text/csv")
+	require.NoError(t, err)
+
+	var response RESTServiceResponse
+	require.NoError(t, val.Get(&response))
+
+	assert.True(t, response.Success)
+	assert.Equal(t, 201, response.StatusCode)
+	assert.Equal(t, "text/csv", receivedContentType)
+	assert.Equal(t, content, received)
+
+	// The SDK throttles heartbeats, so only check that progress was reported
+	require.NotEmpty(t, heartbeats)
+	assert.Greater(t, heartbeats[0], int64(0))
+
+	t.Run("Missing file", func(t *testing.T) {
+		_, err := env.ExecuteActivity(activities.UploadStream,
+			"ReportService", server.URL, "/reports/daily",
+			restclient.AuthConfig{Type: restclient.NoAuth},
+			filepath.Join(t.TempDir(), "missing.csv"), "text/csv")
+
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "failed to open upload file")
+	})
+}
+
+func TestRESTServiceActivities_InvokeAndPoll(t *testing.T) {
+	var offsiteRequests int32
+	offsite := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&offsiteRequests, 1)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer offsite.Close()
+
+	var statusPolls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/reports":
+			assert.Equal(t, "POST", r.Method)
+			w.Header().Set("Location", "/reports/status/42")
+			w.WriteHeader(http.StatusAccepted)
+		case "/reports/offsite":
+			w.Header().Set("Location", offsite.URL+"/status/42")
+			w.WriteHeader(http.StatusAccepted)
+		case "/reports/status/42":
+			assert.Equal(t, "GET", r.Method)
+			state := "processing"
+			if atomic.AddInt32(&statusPolls, 1) >= 3 {
+				state = "done"
+			}
+			w.Header().Set("Content-Type", "application/json")
+			json.NewEncoder(w).Encode(map[string]string{"state": state})
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	testSuite := &testsuite.WorkflowTestSuite{}
+	env := testSuite.NewTestActivityEnvironment()
+
+	var heartbeats int
+	env.SetOnActivityHeartbeatListener(func(activityInfo *activity.Info, details converter.EncodedValues) {
+		heartbeats++
+	})
+
+	activities := NewRESTServiceActivities(&testLogger{})
+
+	isDone := func(resp *RESTServiceResponse) bool {
+		var status map[string]string
+		return json.Unmarshal([]byte(resp.Body), &status) == nil && status["state"] == "done"
+	}
+
+	// InvokeAndPoll takes a predicate, so wrap it in an activity to run it in an activity context
+	generateReport := func(ctx context.Context, endpoint string, maxPolls int) (*RESTServiceResponse, error) {
+		req := RESTServiceRequest{
+			ServiceName: "ReportService",
+			BaseURL:     server.URL,
+			Auth: restclient.AuthConfig{
+				Type:  restclient.BearerAuth,
+				Token: "report-token",
+			},
+			Request: restclient.RESTRequest{
+				Method:   restclient.POST,
+				Endpoint: endpoint,
+				Body:     map[string]string{"type": "monthly"},
+			},
+		}
+		return activities.InvokeAndPoll(ctx, req, 10*time.Millisecond, isDone, maxPolls)
+	}
+	env.RegisterActivityWithOptions(generateReport, activity.RegisterOptions{Name: "GenerateReport"})
+
+	t.Run("Polls until done", func(t *testing.T) {
+		atomic.StoreInt32(&statusPolls, 0)
+
+		val, err := env.ExecuteActivity("GenerateReport", "/reports", 5)
+		require.NoError(t, err)
+
+		var response RESTServiceResponse
+		require.NoError(t, val.Get(&response))
+		assert.Equal(t, 200, response.StatusCode)
+		assert.JSONEq(t, `{"state":"done"}`, response.Body)
+		assert.Equal(t, int32(3), atomic.LoadInt32(&statusPolls))
+		assert.NotZero(t, heartbeats)
+	})
+
+	t.Run("Stops at max polls", func(t *testing.T) {
+		atomic.StoreInt32(&statusPolls, 0)
+
+		_, err := env.ExecuteActivity("GenerateReport", "/reports", 2)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "not complete after 2 polls")
+		assert.Equal(t, int32(2), atomic.LoadInt32(&statusPolls))
+	})
+
+	t.Run("Refuses Location on another host", func(t *testing.T) {
+		_, err := env.ExecuteActivity("GenerateReport", "/reports/offsite", 5)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "not on the same origin")
+		assert.Zero(t, atomic.LoadInt32(&offsiteRequests))
+	})
+}
+
+func TestRESTServiceActivities_DownloadAndVerify(t *testing.T) {
+	payload := bytes.Repeat([]byte("artifact-chunk-0123456789\n"), 10000)
+	sum := sha256.Sum256(payload)
+	expectedSHA256 := hex.EncodeToString(sum[:])
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/artifacts/build.tar":
+			w.Header().Set("Content-Type", "application/octet-stream")
+			w.Write(payload)
+		case "/artifacts/slow.tar":
+			// Trickle the payload out over longer than the client timeout
+			w.Header().Set("Content-Type", "application/octet-stream")
+			chunk := len(payload)/5 + 1
+			for start := 0; start < len(payload); start += chunk {
+				end := start + chunk
+				if end > len(payload) {
+					end = len(payload)
+				}
+				w.Write(payload[start:end])
+				w.(http.Flusher).Flush()
+				time.Sleep(50 * time.Millisecond)
+			}
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	testSuite := &testsuite.WorkflowTestSuite{}
+	env := testSuite.NewTestActivityEnvironment()
+
+	var heartbeats []int64
+	env.SetOnActivityHeartbeatListener(func(activityInfo *activity.Info, details converter.EncodedValues) {
+		var received int64
+		if err := details.Get(&received); err == nil {
+			heartbeats = append(heartbeats, received)
+		}
+	})
+
+	activities := NewRESTServiceActivities(&testLogger{})
+	env.RegisterActivity(activities.DownloadAndVerify)
+
+	newRequest := func(endpoint string) RESTServiceRequest {
+		return RESTServiceRequest{
+			ServiceName: "ArtifactService",
+			BaseURL:     server.URL,
+			Auth:        restclient.AuthConfig{Type: restclient.NoAuth},
+			Request: restclient.RESTRequest{
+				Method:   restclient.GET,
+				Endpoint: endpoint,
+			},
+		}
+	}
+
+	t.Run("Checksum matches", func(t *testing.T) {
+		destPath := filepath.Join(t.TempDir(), "build.tar")
+
+		_, err := env.ExecuteActivity(activities.DownloadAndVerify, newRequest("/artifacts/build.tar"), expectedSHA256, destPath)
+		require.NoError(t, err)
+
+		downloaded, err := os.ReadFile(destPath)
+		require.NoError(t, err)
+		assert.Equal(t, payload, downloaded)
+
+		// The SDK throttles heartbeats, so only check that progress was reported
+		require.NotEmpty(t, heartbeats)
+		assert.Greater(t, heartbeats[0], int64(0))
+	})
+
+	t.Run("Body slower than the client timeout", func(t *testing.T) {
+		req := newRequest("/artifacts/slow.tar")
+		client, err := activities.getClient(req.BaseURL, req.Auth)
+		require.NoError(t, err)
+		client.HTTPClient().Timeout = 100 * time.Millisecond
+		defer func() { client.HTTPClient().Timeout = 30 * time.Second }()
+
+		destPath := filepath.Join(t.TempDir(), "slow.tar")
+		_, err = env.ExecuteActivity(activities.DownloadAndVerify, req, expectedSHA256, destPath)
+		require.NoError(t, err)
+
+		downloaded, err := os.ReadFile(destPath)
+		require.NoError(t, err)
+		assert.Equal(t, payload, downloaded)
+	})
+
+	t.Run("Checksum mismatch removes file", func(t *testing.T) {
+		destPath := filepath.Join(t.TempDir(), "build.tar")
+		wrongSHA256 := strings.Repeat("0", 64)
+
+		_, err := env.ExecuteActivity(activities.DownloadAndVerify, newRequest("/artifacts/build.tar"), wrongSHA256, destPath)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "checksum mismatch")
+		assert.Contains(t, err.Error(), expectedSHA256)
+
+		_, statErr := os.Stat(destPath)
+		assert.True(t, os.IsNotExist(statErr), "partial file should be deleted")
+	})
+
+	t.Run("Non-success status", func(t *testing.T) {
+		destPath := filepath.Join(t.TempDir(), "missing.tar")
+
+		_, err := env.ExecuteActivity(activities.DownloadAndVerify, newRequest("/artifacts/missing.tar"), expectedSHA256, destPath)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "HTTP 404")
+
+		_, statErr := os.Stat(destPath)
+		assert.True(t, os.IsNotExist(statErr))
+	})
+}
+
+func TestRESTServiceActivities_BatchRESTCalls(t *testing.T) {
+	server := createTestServer(t)
+	defer server.Close()
+
+	testSuite := &testsuite.WorkflowTestSuite{}
+	env := testSuite.NewTestActivityEnvironment()
+
+	activities := NewRESTServiceActivities(&testLogger{})
+	env.RegisterActivity(activities.InvokeRESTService)
+	env.RegisterActivity(activities.BatchRESTCalls)
+
+	requests := []RESTServiceRequest{
+		{
+			ServiceName: "UserService",
+			BaseURL:     server.URL,
+			Auth:        restclient.AuthConfig{Type: restclient.NoAuth},
+			Request: restclient.RESTRequest{
+				Method:   restclient.GET,
+				Endpoint: "/users/1",
+			},
+		},
+		{
+			ServiceName: "UserService",
+			BaseURL:     server.URL,
+			Auth:        restclient.AuthConfig{Type: restclient.NoAuth},
+			Request: restclient.RESTRequest{
+				Method:   restclient.POST,
+				Endpoint: "/users",
+				Body: TestUser{
+					Name:  "Batch User",
+					Email: "batch@example.com",
+				},
+			},
+		},
+		{
+			ServiceName: "ErrorService",
+			BaseURL:     server.URL,
+			Auth:        restclient.AuthConfig{Type: restclient.NoAuth},
+			Request: restclient.RESTRequest{
+				Method:   restclient.GET,
+				Endpoint: "/error/500",
+			},
+		},
+	}
+
+	val, err := env.ExecuteActivity(activities.BatchRESTCalls, requests)
+	assert.NoError(t, err)
+
+	var responses []*RESTServiceResponse
+	err = val.Get(&responses)
+	assert.NoError(t, err)
+
+	assert.Len(t, responses, 3)
+
+	// First request should succeed
+	assert.True(t, responses[0].Success)
+	assert.Equal(t, 200, responses[0].StatusCode)
+
+	// Second request should succeed
+	assert.True(t, responses[1].Success)
+	assert.Equal(t, 201, responses[1].StatusCode)
+
+	// Third request should fail
+	assert.False(t, responses[2].Success)
+	assert.Equal(t, 500, responses[2].StatusCode)
+}
+
+func TestRESTServiceActivities_BatchRESTCallsCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// Cancel the activity context as soon as the first request arrives
+	var hits int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&hits, 1)
+		cancel()
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	testSuite := &testsuite.WorkflowTestSuite{}
+	env := testSuite.NewTestActivityEnvironment()
+	env.SetWorkerOptions(worker.Options{BackgroundActivityContext: ctx})
+
+	activities := NewRESTServiceActivities(&testLogger{})
+	env.RegisterActivity(activities.BatchRESTCalls)
+
+	requests := make([]RESTServiceRequest, 3)
+	for i := range requests {
+		requests[i] = RESTServiceRequest{
+			ServiceName: "UserService",
+			BaseURL:     server.URL,
+			Auth:        restclient.AuthConfig{Type: restclient.NoAuth},
+			Request: restclient.RESTRequest{
+				Method:   restclient.GET,
+				Endpoint: fmt.Sprintf("/users/%d", i+1),
+			},
+		}
+	}
+
+	_, err := env.ExecuteActivity(activities.BatchRESTCalls, requests)
+
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), context.Canceled.Error())
+	assert.Equal(t, int32(1), atomic.LoadInt32(&hits))
+}
+
+func TestRESTServiceActivities_BatchRESTCallsWithSummary(t *testing.T) {
+	server := createTestServer(t)
+	defer server.Close()
+
+	testSuite := &testsuite.WorkflowTestSuite{}
+	env := testSuite.NewTestActivityEnvironment()
+
+	activities := NewRESTServiceActivities(&testLogger{})
+	env.RegisterActivity(activities.BatchRESTCallsWithSummary)
+
+	newRequest := func(endpoint string) RESTServiceRequest {
+		return RESTServiceRequest{
+			ServiceName: "UserService",
+			BaseURL:     server.URL,
+			Auth:        restclient.AuthConfig{Type: restclient.NoAuth},
+			Request: restclient.RESTRequest{
+				Method:   restclient.GET,
+				Endpoint: endpoint,
+			},
+		}
+	}
+
+	tests := []struct {
+		name               string
+		endpoints          []string
+		expectedOutcome    BatchOutcome
+		expectedSuccessful int
+		expectedFailed     int
+	}{
+		{
+			name:               "All requests succeed",
+			endpoints:          []string{"/users/1", "/users/1"},
+			expectedOutcome:    AllSucceeded,
+			expectedSuccessful: 2,
+			expectedFailed:     0,
+		},
+		{
+			name:               "Some requests fail",
+			endpoints:          []string{"/users/1", "/error/500", "/users/1"},
+			expectedOutcome:    PartialSuccess,
+			expectedSuccessful: 2,
+			expectedFailed:     1,
+		},
+		{
+			name:               "All requests fail",
+			endpoints:          []string{"/error/500", "/error/400"},
+			expectedOutcome:    AllFailed,
+			expectedSuccessful: 0,
+			expectedFailed:     2,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			requests := make([]RESTServiceRequest, len(tt.endpoints))
+			for i, endpoint := range tt.endpoints {
+				requests[i] = newRequest(endpoint)
+			}
+
+			val, err := env.ExecuteActivity(activities.BatchRESTCallsWithSummary, requests)
+			require.NoError(t, err)
+
+			var summary BatchSummary
+			err = val.Get(&summary)
+			assert.NoError(t, err)
+
+			assert.Equal(t, tt.expectedOutcome, summary.Outcome)
+			assert.Equal(t, len(tt.endpoints), summary.Total)
+			assert.Equal(t, tt.expectedSuccessful, summary.Successful)
+			assert.Equal(t, tt.expectedFailed, summary.Failed)
+			assert.Len(t, summary.Responses, len(tt.endpoints))
+		})
+	}
+}
+
+func TestRESTServiceActivities_BatchRESTCallsStream(t *testing.T) {
+	server := createTestServer(t)
+	defer server.Close()
+
+	testSuite := &testsuite.WorkflowTestSuite{}
+	env := testSuite.NewTestActivityEnvironment()
+
+	activities := NewRESTServiceActivities(&testLogger{})
+
+	// Channels cannot cross the activity boundary, so collect the stream inside an activity
+	collectStream := func(ctx context.Context, requests []RESTServiceRequest) ([]IndexedResponse, error) {
+		stream, err := BatchRESTCallsStream(ctx, activities, requests, 2)
+		if err != nil {
+			return nil, err
+		}
+
+		var collected []IndexedResponse
+		for result := range stream {
+			collected = append(collected, result)
+		}
+		return collected, nil
+	}
+	env.RegisterActivity(collectStream)
+
+	endpoints := []string{"/users/1", "/error/500", "/users/1", "/error/404"}
+	requests := make([]RESTServiceRequest, len(endpoints))
+	for i, endpoint := range endpoints {
+		requests[i] = RESTServiceRequest{
+			ServiceName: "UserService",
+			BaseURL:     server.URL,
+			Auth:        restclient.AuthConfig{Type: restclient.NoAuth},
+			Request: restclient.RESTRequest{
+				Method:   restclient.GET,
+				Endpoint: endpoint,
+			},
+		}
+	}
+
+	val, err := env.ExecuteActivity(collectStream, requests)
+	require.NoError(t, err)
+
+	var collected []IndexedResponse
+	require.NoError(t, val.Get(&collected))
+	require.Len(t, collected, len(requests))
+
+	seen := make(map[int]bool)
+	for _, result := range collected {
+		require.NotNil(t, result.Response)
+		assert.False(t, seen[result.Index], "index %d emitted more than once", result.Index)
+		seen[result.Index] = true
+
+		expectSuccess := !strings.HasPrefix(endpoints[result.Index], "/error")
+		assert.Equal(t, expectSuccess, result.Response.Success, "unexpected result for index %d", result.Index)
+	}
+	for i := range requests {
+		assert.True(t, seen[i], "missing response for index %d", i)
+	}
+
+	t.Run("Empty batch", func(t *testing.T) {
+		_, err := env.ExecuteActivity(collectStream, []RESTServiceRequest{})
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "no requests provided")
+	})
+
+	var inFlight, peak, started int32
+	slow := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&started, 1)
+		current := atomic.AddInt32(&inFlight, 1)
+		defer atomic.AddInt32(&inFlight, -1)
+		for {
+			max := atomic.LoadInt32(&peak)
+			if current <= max || atomic.CompareAndSwapInt32(&peak, max, current) {
+				break
+			}
+		}
+		time.Sleep(20 * time.Millisecond)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer slow.Close()
+
+	slowRequests := make([]RESTServiceRequest, 10)
+	for i := range slowRequests {
+		slowRequests[i] = RESTServiceRequest{
+			ServiceName: "SlowService",
+			BaseURL:     slow.URL,
+			Auth:        restclient.AuthConfig{Type: restclient.NoAuth},
+			Request:     restclient.RESTRequest{Method: restclient.GET, Endpoint: "/slow"},
+		}
+	}
+
+	t.Run("Concurrency is bounded", func(t *testing.T) {
+		atomic.StoreInt32(&peak, 0)
+
+		val, err := env.ExecuteActivity(collectStream, slowRequests)
+		require.NoError(t, err)
+
+		var collected []IndexedResponse
+		require.NoError(t, val.Get(&collected))
+		assert.Len(t, collected, len(slowRequests))
+		assert.LessOrEqual(t, atomic.LoadInt32(&peak), int32(2))
 	})
 
-	t.Run("DeleteResource", func(t *testing.T) {
-		val, err := env.ExecuteActivity(
-			activities.DeleteResource,
-			"UserService",
-			server.URL,
-			"/users/1",
-			auth,
-		)
+	t.Run("Cancellation stops new requests", func(t *testing.T) {
+		atomic.StoreInt32(&started, 0)
 
-		assert.NoError(t, err)
+		// Cancel after the first response; later requests must never be sent
+		cancelAfterFirst := func(ctx context.Context, requests []RESTServiceRequest) (int, error) {
+			ctx, cancel := context.WithCancel(ctx)
+			defer cancel()
 
-		var response RESTServiceResponse
-		err = val.Get(&response)
-		assert.NoError(t, err)
+			stream, err := BatchRESTCallsStream(ctx, activities, requests, 1)
+			if err != nil {
+				return 0, err
+			}
+			received := 0
+			for range stream {
+				received++
+				cancel()
+			}
+			return received, nil
+		}
+		env.RegisterActivity(cancelAfterFirst)
 
-		assert.True(t, response.Success)
-		assert.Equal(t, 204, response.StatusCode)
+		val, err := env.ExecuteActivity(cancelAfterFirst, slowRequests)
+		require.NoError(t, err)
+
+		var received int
+		require.NoError(t, val.Get(&received))
+		assert.Less(t, received, len(slowRequests))
+		assert.LessOrEqual(t, atomic.LoadInt32(&started), int32(2))
 	})
 }
 
-func TestRESTServiceActivities_BatchRESTCalls(t *testing.T) {
+func TestRESTServiceActivities_BatchRESTCallsFailFast(t *testing.T) {
 	server := createTestServer(t)
 	defer server.Close()
 
@@ -496,8 +2602,7 @@ func TestRESTServiceActivities_BatchRESTCalls(t *testing.T) {
 	env := testSuite.NewTestActivityEnvironment()
 
 	activities := NewRESTServiceActivities(&testLogger{})
-	env.RegisterActivity(activities.InvokeRESTService)
-	env.RegisterActivity(activities.BatchRESTCalls)
+	env.RegisterActivity(activities.BatchRESTCallsFailFast)
 
 	requests := []RESTServiceRequest{
 		{
@@ -510,49 +2615,45 @@ func TestRESTServiceActivities_BatchRESTCalls(t *testing.T) {
 			},
 		},
 		{
-			ServiceName: "UserService",
+			ServiceName: "ErrorService",
 			BaseURL:     server.URL,
 			Auth:        restclient.AuthConfig{Type: restclient.NoAuth},
 			Request: restclient.RESTRequest{
-				Method:   restclient.POST,
-				Endpoint: "/users",
-				Body: TestUser{
-					Name:  "Batch User",
-					Email: "batch@example.com",
-				},
+				Method:   restclient.GET,
+				Endpoint: "/error/500",
 			},
 		},
 		{
-			ServiceName: "ErrorService",
+			ServiceName: "UserService",
 			BaseURL:     server.URL,
 			Auth:        restclient.AuthConfig{Type: restclient.NoAuth},
 			Request: restclient.RESTRequest{
 				Method:   restclient.GET,
-				Endpoint: "/error/500",
+				Endpoint: "/users/1",
 			},
 		},
 	}
 
-	val, err := env.ExecuteActivity(activities.BatchRESTCalls, requests)
-	assert.NoError(t, err)
+	_, err := env.ExecuteActivity(activities.BatchRESTCallsFailFast, requests)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "index 1")
+
+	var appErr *temporal.ApplicationError
+	require.True(t, errors.As(err, &appErr))
 
 	var responses []*RESTServiceResponse
-	err = val.Get(&responses)
+	err = appErr.Details(&responses)
 	assert.NoError(t, err)
 
-	assert.Len(t, responses, 3)
+	assert.Len(t, responses, 2)
 
 	// First request should succeed
 	assert.True(t, responses[0].Success)
 	assert.Equal(t, 200, responses[0].StatusCode)
 
-	// Second request should succeed
-	assert.True(t, responses[1].Success)
-	assert.Equal(t, 201, responses[1].StatusCode)
-
-	// Third request should fail
-	assert.False(t, responses[2].Success)
-	assert.Equal(t, 500, responses[2].StatusCode)
+	// Second request should fail and stop the batch
+	assert.False(t, responses[1].Success)
+	assert.Equal(t, 500, responses[1].StatusCode)
 }
 
 func TestRESTServiceActivities_ValidateRESTResponse(t *testing.T) {
@@ -653,6 +2754,463 @@ func TestRESTServiceActivities_ValidateRESTResponse(t *testing.T) {
 	}
 }
 
+func TestRESTServiceActivities_ValidateContentType(t *testing.T) {
+	testSuite := &testsuite.WorkflowTestSuite{}
+	env := testSuite.NewTestActivityEnvironment()
+
+	activities := NewRESTServiceActivities(&testLogger{})
+	env.RegisterActivity(activities.ValidateContentType)
+
+	tests := []struct {
+		name                string
+		contentType         string
+		expectedContentType string
+		expectError         bool
+	}{
+		{
+			name:                "Exact match",
+			contentType:         "application/json",
+			expectedContentType: "application/json",
+		},
+		{
+			name:                "Charset suffix",
+			contentType:         "application/json; charset=utf-8",
+			expectedContentType: "application/json",
+		},
+		{
+			name:                "Case insensitive",
+			contentType:         "Application/JSON;charset=UTF-8",
+			expectedContentType: "application/json",
+		},
+		{
+			name:                "HTML error page with 200 status",
+			contentType:         "text/html; charset=utf-8",
+			expectedContentType: "application/json",
+			expectError:         true,
+		},
+		{
+			name:                "Missing content type",
+			contentType:         "",
+			expectedContentType: "application/json",
+			expectError:         true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			response := &RESTServiceResponse{
+				ServiceName: "UserService",
+				StatusCode:  200,
+				ContentType: tt.contentType,
+				Body:        `<html><body>Bad Gateway</body></html>`,
+				Success:     true,
+			}
+
+			_, err := env.ExecuteActivity(activities.ValidateContentType, response, tt.expectedContentType)
+
+			if tt.expectError {
+				assert.Error(t, err)
+				assert.Contains(t, err.Error(), "expected content type application/json")
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestRESTServiceActivities_ValidateResponseHeaders(t *testing.T) {
+	testSuite := &testsuite.WorkflowTestSuite{}
+	env := testSuite.NewTestActivityEnvironment()
+
+	activities := NewRESTServiceActivities(&testLogger{})
+	env.RegisterActivity(activities.ValidateResponseHeaders)
+
+	response := &RESTServiceResponse{
+		ServiceName: "UserService",
+		StatusCode:  200,
+		Headers: map[string][]string{
+			"Content-Type":          {"application/json"},
+			"X-Ratelimit-Remaining": {"42"},
+			"x-trace-id":            {"abc123"}, // Non-canonical key
+		},
+		Success: true,
+	}
+
+	tests := []struct {
+		name          string
+		required      map[string]string
+		expectError   bool
+		errorContains string
+	}{
+		{
+			name:     "Exist only",
+			required: map[string]string{"X-RateLimit-Remaining": "", "X-Trace-Id": ""},
+		},
+		{
+			name:     "Exact match",
+			required: map[string]string{"content-type": "application/json", "X-Trace-ID": "abc123"},
+		},
+		{
+			name:          "Value mismatch",
+			required:      map[string]string{"Content-Type": "application/xml"},
+			expectError:   true,
+			errorContains: "header 'Content-Type' expected 'application/xml', got 'application/json'",
+		},
+		{
+			name:          "Missing header",
+			required:      map[string]string{"X-RateLimit-Reset": ""},
+			expectError:   true,
+			errorContains: "required header 'X-RateLimit-Reset' not found",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := env.ExecuteActivity(activities.ValidateResponseHeaders, response, tt.required)
+
+			if tt.expectError {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.errorContains)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestRESTServiceActivities_ValidateBodyPattern(t *testing.T) {
+	testSuite := &testsuite.WorkflowTestSuite{}
+	env := testSuite.NewTestActivityEnvironment()
+
+	activities := NewRESTServiceActivities(&testLogger{})
+	env.RegisterActivity(activities.ValidateBodyPattern)
+
+	response := &RESTServiceResponse{
+		ServiceName: "StatusPage",
+		StatusCode:  200,
+		ContentType: "text/html; charset=utf-8",
+		Body:        `<html><body>All systems operational (build 1.4.2)</body></html>`,
+		Success:     true,
+	}
+
+	tests := []struct {
+		name          string
+		pattern       string
+		expectError   bool
+		errorContains string
+	}{
+		{
+			name:    "Matching pattern",
+			pattern: `build \d+\.\d+\.\d+`,
+		},
+		{
+			name:          "Non-matching pattern",
+			pattern:       `degraded|outage`,
+			expectError:   true,
+			errorContains: "does not match pattern",
+		},
+		{
+			name:          "Invalid regex",
+			pattern:       `build (\d+`,
+			expectError:   true,
+			errorContains: "invalid body pattern",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := env.ExecuteActivity(activities.ValidateBodyPattern, response, tt.pattern)
+
+			if tt.expectError {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.errorContains)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+
+	t.Run("Invalid regex is not retryable", func(t *testing.T) {
+		_, err := env.ExecuteActivity(activities.ValidateBodyPattern, response, `[`)
+
+		var appErr *temporal.ApplicationError
+		require.True(t, errors.As(err, &appErr))
+		assert.True(t, appErr.NonRetryable())
+	})
+}
+
+func TestRESTServiceActivities_HealthCheck(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/health":
+			json.NewEncoder(w).Encode(map[string]string{"status": "ok"})
+		case "/health/degraded":
+			json.NewEncoder(w).Encode(map[string]string{"status": "degraded"})
+		case "/health/nested":
+			json.NewEncoder(w).Encode(map[string]interface{}{
+				"checks": map[string]string{"database": "ok"},
+			})
+		}
+	}))
+	defer server.Close()
+
+	testSuite := &testsuite.WorkflowTestSuite{}
+	env := testSuite.NewTestActivityEnvironment()
+
+	activities := NewRESTServiceActivities(&testLogger{})
+	env.RegisterActivity(activities.HealthCheck)
+
+	tests := []struct {
+		name          string
+		endpoint      string
+		field         string
+		value         string
+		expectHealthy bool
+		expectError   string
+	}{
+		{
+			name:          "Status code only",
+			endpoint:      "/health/degraded",
+			expectHealthy: true,
+		},
+		{
+			name:          "Matching status field",
+			endpoint:      "/health",
+			field:         "status",
+			value:         "ok",
+			expectHealthy: true,
+		},
+		{
+			name:          "Degraded but 200",
+			endpoint:      "/health/degraded",
+			field:         "status",
+			value:         "ok",
+			expectHealthy: false,
+			expectError:   "expected 'ok'",
+		},
+		{
+			name:          "Nested status field",
+			endpoint:      "/health/nested",
+			field:         "checks.database",
+			value:         "ok",
+			expectHealthy: true,
+		},
+		{
+			name:          "Missing status field",
+			endpoint:      "/health/nested",
+			field:         "status",
+			value:         "ok",
+			expectHealthy: false,
+			expectError:   "not found",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := HealthCheckRequest{
+				ServiceName:         "StatusService",
+				BaseURL:             server.URL,
+				Auth:                restclient.AuthConfig{Type: restclient.NoAuth},
+				Endpoint:            tt.endpoint,
+				ExpectedStatusField: tt.field,
+				ExpectedStatusValue: tt.value,
+			}
+
+			val, err := env.ExecuteActivity(activities.HealthCheck, req)
+			require.NoError(t, err)
+
+			var response HealthCheckResponse
+			require.NoError(t, val.Get(&response))
+
+			assert.Equal(t, 200, response.StatusCode)
+			assert.Equal(t, tt.expectHealthy, response.IsHealthy)
+			if tt.expectError != "" {
+				assert.Contains(t, response.ErrorMessage, tt.expectError)
+			}
+		})
+	}
+}
+
+func TestRESTServiceActivities_Ping(t *testing.T) {
+	testSuite := &testsuite.WorkflowTestSuite{}
+	env := testSuite.NewTestActivityEnvironment()
+
+	activities := NewRESTServiceActivities(&testLogger{})
+	env.RegisterActivity(activities.Ping)
+
+	ping := func(t *testing.T, baseURL string) PingResult {
+		val, err := env.ExecuteActivity(activities.Ping, baseURL, 2*time.Second)
+		require.NoError(t, err)
+
+		var result PingResult
+		require.NoError(t, val.Get(&result))
+		return result
+	}
+
+	t.Run("Reachable server", func(t *testing.T) {
+		var requests int32
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			atomic.AddInt32(&requests, 1)
+		}))
+		defer server.Close()
+
+		result := ping(t, server.URL)
+		assert.True(t, result.Reachable)
+		assert.Empty(t, result.Failure)
+		assert.Greater(t, result.Latency, time.Duration(0))
+		assert.Equal(t, strings.TrimPrefix(server.URL, "http://"), result.Address)
+		assert.Zero(t, atomic.LoadInt32(&requests), "ping should not send an HTTP request")
+	})
+
+	t.Run("Closed port", func(t *testing.T) {
+		server := httptest.NewServer(http.NotFoundHandler())
+		closedURL := server.URL
+		server.Close()
+
+		result := ping(t, closedURL)
+		assert.False(t, result.Reachable)
+		assert.Equal(t, PingConnectionRefused, result.Failure)
+		assert.NotEmpty(t, result.ErrorMessage)
+	})
+
+	t.Run("Untrusted certificate", func(t *testing.T) {
+		server := httptest.NewTLSServer(http.NotFoundHandler())
+		defer server.Close()
+
+		result := ping(t, server.URL)
+		assert.False(t, result.Reachable)
+		assert.Equal(t, PingTLSFailure, result.Failure)
+	})
+
+	t.Run("Unknown host", func(t *testing.T) {
+		result := ping(t, "http://does-not-exist.invalid")
+		assert.False(t, result.Reachable)
+		assert.Equal(t, PingDNSFailure, result.Failure)
+		assert.Equal(t, "does-not-exist.invalid:80", result.Address)
+	})
+
+	t.Run("Invalid URL", func(t *testing.T) {
+		_, err := env.ExecuteActivity(activities.Ping, "not a url", time.Second)
+		require.Error(t, err)
+
+		var appErr *temporal.ApplicationError
+		require.True(t, errors.As(err, &appErr))
+		assert.True(t, appErr.NonRetryable())
+	})
+}
+
+func TestRESTServiceActivities_BatchHealthCheck(t *testing.T) {
+	healthy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]string{"status": "ok"})
+	}))
+	defer healthy.Close()
+
+	failing := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer failing.Close()
+
+	slow := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(time.Second)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer slow.Close()
+
+	testSuite := &testsuite.WorkflowTestSuite{}
+	env := testSuite.NewTestActivityEnvironment()
+
+	activities := NewRESTServiceActivities(&testLogger{})
+	env.RegisterActivity(activities.BatchHealthCheck)
+
+	reqs := []HealthCheckRequest{
+		{ServiceName: "HealthyService", BaseURL: healthy.URL, Auth: restclient.AuthConfig{Type: restclient.NoAuth}},
+		{ServiceName: "FailingService", BaseURL: failing.URL, Auth: restclient.AuthConfig{Type: restclient.NoAuth}},
+		{ServiceName: "SlowService", BaseURL: slow.URL, Auth: restclient.AuthConfig{Type: restclient.NoAuth}, Timeout: 200 * time.Millisecond},
+	}
+
+	val, err := env.ExecuteActivity(activities.BatchHealthCheck, reqs, 0)
+	require.NoError(t, err)
+
+	var results []*HealthCheckResponse
+	require.NoError(t, val.Get(&results))
+	require.Len(t, results, 3)
+
+	// Results are returned in input order
+	assert.Equal(t, "HealthyService", results[0].ServiceName)
+	assert.True(t, results[0].IsHealthy)
+	assert.Equal(t, 200, results[0].StatusCode)
+
+	assert.Equal(t, "FailingService", results[1].ServiceName)
+	assert.False(t, results[1].IsHealthy)
+	assert.Equal(t, 500, results[1].StatusCode)
+
+	assert.Equal(t, "SlowService", results[2].ServiceName)
+	assert.False(t, results[2].IsHealthy)
+	assert.NotEmpty(t, results[2].ErrorMessage)
+}
+
+func TestRESTServiceActivities_BatchHealthCheckConcurrency(t *testing.T) {
+	var inFlight, peak int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		current := atomic.AddInt32(&inFlight, 1)
+		defer atomic.AddInt32(&inFlight, -1)
+		for {
+			max := atomic.LoadInt32(&peak)
+			if current <= max || atomic.CompareAndSwapInt32(&peak, max, current) {
+				break
+			}
+		}
+		time.Sleep(20 * time.Millisecond)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	testSuite := &testsuite.WorkflowTestSuite{}
+	env := testSuite.NewTestActivityEnvironment()
+
+	activities := NewRESTServiceActivities(&testLogger{})
+	env.RegisterActivity(activities.BatchHealthCheck)
+
+	reqs := make([]HealthCheckRequest, 20)
+	for i := range reqs {
+		reqs[i] = HealthCheckRequest{
+			ServiceName: fmt.Sprintf("Service-%d", i),
+			BaseURL:     server.URL,
+			Auth:        restclient.AuthConfig{Type: restclient.NoAuth},
+		}
+	}
+
+	tests := []struct {
+		name           string
+		maxConcurrency int
+		expectedPeak   int32
+	}{
+		{name: "Explicit bound", maxConcurrency: 3, expectedPeak: 3},
+		{name: "Default bound", maxConcurrency: 0, expectedPeak: DefaultMaxConcurrentHealthChecks},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			atomic.StoreInt32(&peak, 0)
+
+			val, err := env.ExecuteActivity(activities.BatchHealthCheck, reqs, tt.maxConcurrency)
+			require.NoError(t, err)
+
+			var results []*HealthCheckResponse
+			require.NoError(t, val.Get(&results))
+			require.Len(t, results, len(reqs))
+			for _, result := range results {
+				assert.True(t, result.IsHealthy)
+			}
+
+			assert.LessOrEqual(t, atomic.LoadInt32(&peak), tt.expectedPeak)
+			assert.Greater(t, atomic.LoadInt32(&peak), int32(1), "checks should run concurrently")
+		})
+	}
+}
+
 func TestRESTServiceActivities_Timeout(t *testing.T) {
 	server := createTestServer(t)
 	defer server.Close()
@@ -1,17 +1,39 @@
 package restclient
 
 import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
 	"context"
+	"crypto/tls"
+	"crypto/x509"
 	"encoding/json"
+	"encoding/xml"
+	"errors"
 	"fmt"
+	"io"
+	"log/slog"
+	"net"
 	"net/http"
 	"net/http/httptest"
+	"net/http/httptrace"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/trace"
+	"golang.org/x/oauth2"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/wrapperspb"
 )
 
 // Test data structures
@@ -259,6 +281,376 @@ func TestNewRESTClient(t *testing.T) {
 	}
 }
 
+func TestJoinURL(t *testing.T) {
+	tests := []struct {
+		name     string
+		base     string
+		endpoint string
+		expected string
+	}{
+		{
+			name:     "Simple join",
+			base:     "https://api.example.com",
+			endpoint: "users",
+			expected: "https://api.example.com/users",
+		},
+		{
+			name:     "Leading and trailing slashes",
+			base:     "https://api.example.com/",
+			endpoint: "/users",
+			expected: "https://api.example.com/users",
+		},
+		{
+			name:     "Repeated slashes",
+			base:     "https://api.example.com//",
+			endpoint: "//users/1",
+			expected: "https://api.example.com/users/1",
+		},
+		{
+			name:     "Empty endpoint",
+			base:     "https://api.example.com/",
+			endpoint: "",
+			expected: "https://api.example.com",
+		},
+		{
+			name:     "Slash-only endpoint",
+			base:     "https://api.example.com",
+			endpoint: "/",
+			expected: "https://api.example.com",
+		},
+		{
+			name:     "Endpoint with query",
+			base:     "https://api.example.com",
+			endpoint: "/users?limit=5",
+			expected: "https://api.example.com/users?limit=5",
+		},
+		{
+			name:     "Query-only endpoint",
+			base:     "https://api.example.com/users/",
+			endpoint: "?limit=5",
+			expected: "https://api.example.com/users?limit=5",
+		},
+		{
+			name:     "Base with path",
+			base:     "https://api.example.com/v1/",
+			endpoint: "/users",
+			expected: "https://api.example.com/v1/users",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, joinURL(tt.base, tt.endpoint))
+		})
+	}
+}
+
+func TestRESTClient_RequestBaseURL(t *testing.T) {
+	newServer := func(name string) *httptest.Server {
+		return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			json.NewEncoder(w).Encode(map[string]string{"server": name, "path": r.URL.Path})
+		}))
+	}
+
+	defaultServer := newServer("default")
+	defer defaultServer.Close()
+	serverA := newServer("a")
+	defer serverA.Close()
+	serverB := newServer("b")
+	defer serverB.Close()
+
+	client, err := NewRESTClient(defaultServer.URL, AuthConfig{Type: NoAuth})
+	require.NoError(t, err)
+
+	tests := []struct {
+		name           string
+		baseURL        string
+		expectedServer string
+	}{
+		{name: "Request base URL A", baseURL: serverA.URL, expectedServer: "a"},
+		{name: "Request base URL B with trailing slash", baseURL: serverB.URL + "/", expectedServer: "b"},
+		{name: "Falls back to client base URL", baseURL: "", expectedServer: "default"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			resp, err := client.Execute(ctx, RESTRequest{
+				BaseURL:  tt.baseURL,
+				Method:   GET,
+				Endpoint: "/users/1",
+			})
+
+			require.NoError(t, err)
+
+			var body map[string]string
+			err = json.Unmarshal(resp.Body, &body)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedServer, body["server"])
+			assert.Equal(t, "/users/1", body["path"])
+		})
+	}
+}
+
+func TestRESTClient_SetBaseURL(t *testing.T) {
+	newServer := func(name string) *httptest.Server {
+		return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			json.NewEncoder(w).Encode(map[string]string{"server": name, "path": r.URL.Path})
+		}))
+	}
+
+	primary := newServer("primary")
+	defer primary.Close()
+	secondary := newServer("secondary")
+	defer secondary.Close()
+
+	client, err := NewRESTClient(primary.URL, AuthConfig{Type: NoAuth})
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	serverName := func() string {
+		resp, err := client.GET(ctx, "/users/1", nil)
+		require.NoError(t, err)
+
+		var body map[string]string
+		require.NoError(t, json.Unmarshal(resp.Body, &body))
+		assert.Equal(t, "/users/1", body["path"])
+		return body["server"]
+	}
+
+	assert.Equal(t, "primary", serverName())
+
+	client.SetBaseURL(secondary.URL + "/")
+	assert.Equal(t, secondary.URL, client.baseURL)
+	assert.Equal(t, "secondary", serverName())
+
+	// Switching while requests are in flight is safe; run with -race
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			client.SetBaseURL(primary.URL)
+		}()
+		go func() {
+			defer wg.Done()
+			_, err := client.GET(ctx, "/users/1", nil)
+			assert.NoError(t, err)
+		}()
+	}
+	wg.Wait()
+	assert.Equal(t, "primary", serverName())
+}
+
+func TestRESTClient_BasePath(t *testing.T) {
+	tests := []struct {
+		name     string
+		baseURL  string
+		basePath string
+		endpoint string
+		expected string
+	}{
+		{
+			name:     "Prefixes endpoint",
+			baseURL:  "https://api.example.com",
+			basePath: "/api/v2",
+			endpoint: "/users/1",
+			expected: "https://api.example.com/api/v2/users/1",
+		},
+		{
+			name:     "Avoids double slashes",
+			baseURL:  "https://api.example.com/",
+			basePath: "/api/v2/",
+			endpoint: "/users",
+			expected: "https://api.example.com/api/v2/users",
+		},
+		{
+			name:     "Prefix without slashes",
+			baseURL:  "https://api.example.com",
+			basePath: "api/v2",
+			endpoint: "users",
+			expected: "https://api.example.com/api/v2/users",
+		},
+		{
+			name:     "Query-only endpoint",
+			baseURL:  "https://api.example.com",
+			basePath: "/api/v2",
+			endpoint: "?limit=5",
+			expected: "https://api.example.com/api/v2?limit=5",
+		},
+		{
+			name:     "Absolute endpoint bypasses base URL and path",
+			baseURL:  "https://api.example.com",
+			basePath: "/api/v2",
+			endpoint: "https://files.example.com/downloads/1",
+			expected: "https://files.example.com/downloads/1",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client, err := NewRESTClientWithOptions(tt.baseURL, AuthConfig{Type: NoAuth}, Options{BasePath: tt.basePath})
+			require.NoError(t, err)
+
+			assert.Equal(t, tt.expected, client.buildURL("", tt.endpoint, nil, nil, nil))
+		})
+	}
+
+	t.Run("Request base URL is not prefixed", func(t *testing.T) {
+		client, err := NewRESTClient("https://api.example.com", AuthConfig{Type: NoAuth})
+		require.NoError(t, err)
+		client.WithBasePath("/api/v2")
+
+		assert.Equal(t, "https://other.example.com/users", client.buildURL("https://other.example.com", "/users", nil, nil, nil))
+	})
+
+	t.Run("Sent request path", func(t *testing.T) {
+		var receivedPath string
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			receivedPath = r.URL.Path
+			w.WriteHeader(http.StatusOK)
+		}))
+		defer server.Close()
+
+		client, err := NewRESTClientWithOptions(server.URL, AuthConfig{Type: NoAuth}, Options{BasePath: "/api/v2"})
+		require.NoError(t, err)
+
+		_, err = client.GET(context.Background(), "/users/1", map[string]string{"expand": "roles"})
+		require.NoError(t, err)
+		assert.Equal(t, "/api/v2/users/1", receivedPath)
+	})
+}
+
+func TestRESTClient_ArrayEncoding(t *testing.T) {
+	ids := []string{"1", "2", "3"}
+
+	tests := []struct {
+		name        string
+		encoding    ArrayEncoding
+		expected    url.Values
+		expectedRaw string
+	}{
+		{
+			name:        "Default repeats keys",
+			encoding:    "",
+			expected:    url.Values{"id": ids, "limit": {"5"}},
+			expectedRaw: "id=1&id=2&id=3&limit=5",
+		},
+		{
+			name:        "Repeat",
+			encoding:    ArrayRepeat,
+			expected:    url.Values{"id": ids, "limit": {"5"}},
+			expectedRaw: "id=1&id=2&id=3&limit=5",
+		},
+		{
+			name:        "Brackets",
+			encoding:    ArrayBrackets,
+			expected:    url.Values{"id[]": ids, "limit": {"5"}},
+			expectedRaw: "id%5B%5D=1&id%5B%5D=2&id%5B%5D=3&limit=5",
+		},
+		{
+			name:        "Comma",
+			encoding:    ArrayComma,
+			expected:    url.Values{"id": {"1,2,3"}, "limit": {"5"}},
+			expectedRaw: "id=1,2,3&limit=5",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var received url.Values
+			var receivedRaw string
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				received = r.URL.Query()
+				receivedRaw = r.URL.RawQuery
+				w.WriteHeader(http.StatusOK)
+			}))
+			defer server.Close()
+
+			client, err := NewRESTClient(server.URL, AuthConfig{Type: NoAuth})
+			require.NoError(t, err)
+			client.ArrayEncoding = tt.encoding
+
+			_, err = client.Execute(context.Background(), RESTRequest{
+				Method:           GET,
+				Endpoint:         "/users",
+				QueryParams:      map[string]string{"limit": "5"},
+				QueryParamsMulti: map[string][]string{"id": ids},
+			})
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, received)
+			assert.Equal(t, tt.expectedRaw, receivedRaw)
+		})
+	}
+
+	t.Run("Comma escapes values but not the separator", func(t *testing.T) {
+		client, err := NewRESTClient("https://api.example.com", AuthConfig{Type: NoAuth})
+		require.NoError(t, err)
+		client.ArrayEncoding = ArrayComma
+
+		fullURL := client.buildURL("", "/search", nil, map[string][]string{"tag": {"a b", "c&d"}}, nil)
+		assert.Equal(t, "https://api.example.com/search?tag=a+b,c%26d", fullURL)
+	})
+}
+
+func TestRESTClient_RawQuery(t *testing.T) {
+	tests := []struct {
+		name        string
+		queryParams map[string]string
+		rawQuery    url.Values
+		expected    url.Values
+	}{
+		{
+			name:     "RawQuery alone",
+			rawQuery: url.Values{"q": {"status:open"}, "tag": {"a", "b"}},
+			expected: url.Values{"q": {"status:open"}, "tag": {"a", "b"}},
+		},
+		{
+			name:        "Combined with QueryParams",
+			queryParams: map[string]string{"limit": "10"},
+			rawQuery:    url.Values{"tag": {"a", "b"}},
+			expected:    url.Values{"limit": {"10"}, "tag": {"a", "b"}},
+		},
+		{
+			name:        "RawQuery wins on conflicting keys",
+			queryParams: map[string]string{"limit": "10", "sort": "name"},
+			rawQuery:    url.Values{"sort": {"created", "id"}},
+			expected:    url.Values{"limit": {"10"}, "sort": {"created", "id"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var received url.Values
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				received = r.URL.Query()
+				w.WriteHeader(http.StatusOK)
+			}))
+			defer server.Close()
+
+			client, err := NewRESTClient(server.URL, AuthConfig{Type: NoAuth})
+			require.NoError(t, err)
+
+			rawQuery := url.Values{}
+			for key, values := range tt.rawQuery {
+				rawQuery[key] = append([]string(nil), values...)
+			}
+
+			_, err = client.Execute(context.Background(), RESTRequest{
+				Method:      GET,
+				Endpoint:    "/search",
+				QueryParams: tt.queryParams,
+				RawQuery:    rawQuery,
+			})
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, received)
+			assert.Equal(t, tt.rawQuery, rawQuery, "RawQuery must not be mutated")
+		})
+	}
+}
+
 func TestRESTClient_GET(t *testing.T) {
 	server := createTestServer(t)
 	defer server.Close()
@@ -434,264 +826,3339 @@ func TestRESTClient_DELETE(t *testing.T) {
 	assert.True(t, resp.IsSuccess())
 }
 
-func TestRESTClient_Authentication(t *testing.T) {
-	server := createTestServer(t)
-	defer server.Close()
-
-	tests := []struct {
-		name           string
-		auth           AuthConfig
-		endpoint       string
-		expectedStatus int
-	}{
-		{
-			name: "Basic auth - success",
-			auth: AuthConfig{
-				Type:     BasicAuth,
-				Username: "testuser",
-				Password: "testpass",
-			},
-			endpoint:       "/auth/basic",
-			expectedStatus: 200,
-		},
-		{
-			name: "Basic auth - failure",
-			auth: AuthConfig{
-				Type:     BasicAuth,
-				Username: "wronguser",
-				Password: "wrongpass",
-			},
-			endpoint:       "/auth/basic",
-			expectedStatus: 401,
-		},
-		{
-			name: "Bearer auth - success",
-			auth: AuthConfig{
-				Type:  BearerAuth,
-				Token: "test-token-123",
-			},
-			endpoint:       "/auth/bearer",
-			expectedStatus: 200,
-		},
-		{
-			name: "Bearer auth - failure",
-			auth: AuthConfig{
-				Type:  BearerAuth,
-				Token: "wrong-token",
-			},
-			endpoint:       "/auth/bearer",
-			expectedStatus: 401,
-		},
-		{
-			name: "API Key auth - success",
-			auth: AuthConfig{
-				Type:   APIKeyAuth,
-				APIKey: "test-api-key-456",
-			},
-			endpoint:       "/auth/apikey",
-			expectedStatus: 200,
-		},
-		{
-			name: "API Key auth - failure",
-			auth: AuthConfig{
-				Type:   APIKeyAuth,
-				APIKey: "wrong-api-key",
-			},
-			endpoint:       "/auth/apikey",
-			expectedStatus: 401,
-		},
-	}
-
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			client, err := NewRESTClient(server.URL, tt.auth)
-			require.NoError(t, err)
-
-			ctx := context.Background()
-			resp, err := client.GET(ctx, tt.endpoint, nil)
+func TestRESTClient_DELETEWithBody(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "DELETE", r.Method)
+		assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
 
-			assert.NoError(t, err)
-			assert.NotNil(t, resp)
-			assert.Equal(t, tt.expectedStatus, resp.StatusCode)
-		})
-	}
-}
+		var payload map[string][]int
+		err := json.NewDecoder(r.Body).Decode(&payload)
+		assert.NoError(t, err)
 
-func TestRESTClient_Timeout(t *testing.T) {
-	server := createTestServer(t)
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]int{"deleted": len(payload["ids"])})
+	}))
 	defer server.Close()
 
 	client, err := NewRESTClient(server.URL, AuthConfig{Type: NoAuth})
 	require.NoError(t, err)
 
 	ctx := context.Background()
-	req := RESTRequest{
-		Method:   GET,
-		Endpoint: "/delay",
-		Timeout:  1 * time.Second, // Shorter than server delay
-	}
+	resp, err := client.DELETEWithBody(ctx, "/users/bulk", map[string][]int{"ids": {1, 2, 3}})
 
-	start := time.Now()
-	resp, err := client.Execute(ctx, req)
-	duration := time.Since(start)
+	assert.NoError(t, err)
+	assert.NotNil(t, resp)
+	assert.Equal(t, 200, resp.StatusCode)
 
-	assert.Error(t, err)
-	assert.Nil(t, resp)
-	assert.True(t, duration < 2*time.Second, "Request should have timed out before 2 seconds")
+	var result map[string]int
+	err = json.Unmarshal(resp.Body, &result)
+	assert.NoError(t, err)
+	assert.Equal(t, 3, result["deleted"])
 }
 
-func TestRESTClient_ErrorStatusCodes(t *testing.T) {
-	server := createTestServer(t)
+func TestRESTClient_GETWithBody(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "GET", r.Method)
+		assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+		assert.Equal(t, "10", r.URL.Query().Get("size"))
+
+		body, err := io.ReadAll(r.Body)
+		assert.NoError(t, err)
+		assert.JSONEq(t, `{"query":{"match":{"name":"john"}}}`, string(body))
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"hits":{"total":1}}`))
+	}))
 	defer server.Close()
 
 	client, err := NewRESTClient(server.URL, AuthConfig{Type: NoAuth})
 	require.NoError(t, err)
 
-	tests := []struct {
-		name           string
-		endpoint       string
-		expectedStatus int
-		checkMethod    func(resp *RESTResponse) bool
-	}{
+	query := map[string]interface{}{
+		"query": map[string]interface{}{
+			"match": map[string]string{"name": "john"},
+		},
+	}
+
+	ctx := context.Background()
+	resp, err := client.GETWithBody(ctx, "/users/_search", query, map[string]string{"size": "10"})
+
+	require.NoError(t, err)
+	assert.Equal(t, 200, resp.StatusCode)
+
+	total, err := resp.GetField("hits.total")
+	assert.NoError(t, err)
+	assert.Equal(t, float64(1), total)
+}
+
+func TestRESTClient_GenerateRequestID(t *testing.T) {
+	var receivedIDs []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		receivedIDs = append(receivedIDs, r.Header.Get("X-Request-ID"))
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	client, err := NewRESTClient(server.URL, AuthConfig{Type: NoAuth})
+	require.NoError(t, err)
+	client.GenerateRequestID = true
+
+	ctx := context.Background()
+	uuidPattern := regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`)
+
+	t.Run("Generated when absent", func(t *testing.T) {
+		receivedIDs = nil
+
+		first, err := client.GET(ctx, "/users", nil)
+		require.NoError(t, err)
+		second, err := client.GET(ctx, "/users", nil)
+		require.NoError(t, err)
+
+		require.Len(t, receivedIDs, 2)
+		assert.Regexp(t, uuidPattern, receivedIDs[0])
+		assert.Equal(t, receivedIDs[0], first.RequestID)
+		assert.Equal(t, receivedIDs[1], second.RequestID)
+		assert.NotEqual(t, first.RequestID, second.RequestID)
+	})
+
+	t.Run("Preserved when supplied", func(t *testing.T) {
+		receivedIDs = nil
+		headers := map[string]string{"x-request-id": "caller-id-123"}
+
+		resp, err := client.Execute(ctx, RESTRequest{
+			Method:   GET,
+			Endpoint: "/users",
+			Headers:  headers,
+		})
+		require.NoError(t, err)
+
+		assert.Equal(t, []string{"caller-id-123"}, receivedIDs)
+		assert.Equal(t, "caller-id-123", resp.RequestID)
+		assert.Len(t, headers, 1, "caller headers should not be mutated")
+	})
+
+	t.Run("Disabled by default", func(t *testing.T) {
+		receivedIDs = nil
+		plain, err := NewRESTClient(server.URL, AuthConfig{Type: NoAuth})
+		require.NoError(t, err)
+
+		resp, err := plain.GET(ctx, "/users", nil)
+		require.NoError(t, err)
+		assert.Equal(t, []string{""}, receivedIDs)
+		assert.Empty(t, resp.RequestID)
+	})
+}
+
+func TestRESTClient_FinalURL(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/users/old" {
+			http.Redirect(w, r, "/v2/users/1?source=redirect", http.StatusFound)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"id":1,"name":"John Doe"}`))
+	}))
+	defer server.Close()
+
+	client, err := NewRESTClient(server.URL, AuthConfig{Type: NoAuth})
+	require.NoError(t, err)
+
+	ctx := context.Background()
+
+	t.Run("Redirected", func(t *testing.T) {
+		resp, err := client.GET(ctx, "/users/old", nil)
+		require.NoError(t, err)
+
+		assert.Equal(t, 200, resp.StatusCode)
+		assert.Equal(t, server.URL+"/users/old", resp.URL)
+		assert.Equal(t, server.URL+"/v2/users/1?source=redirect", resp.FinalURL)
+	})
+
+	t.Run("Not redirected", func(t *testing.T) {
+		resp, err := client.GET(ctx, "/v2/users/1", nil)
+		require.NoError(t, err)
+
+		assert.Equal(t, resp.URL, resp.FinalURL)
+	})
+}
+
+
+func TestTruncateBody(t *testing.T) {
+	tests := []struct {
+		name     string
+		body     string
+		max      int
+		expected string
+	}{
+		{name: "Short body", body: "hello", max: 10, expected: "hello"},
+		{name: "Exact length", body: "hello", max: 5, expected: "hello"},
+		{name: "Truncated", body: "hello world", max: 5, expected: "hello... (11 bytes total)"},
+		{name: "UTF-8 boundary", body: "héllo", max: 2, expected: "h... (6 bytes total)"},
+		{name: "Default limit", body: strings.Repeat("a", DefaultMaxLoggedBodyBytes+1), max: 0,
+			expected: strings.Repeat("a", DefaultMaxLoggedBodyBytes) + fmt.Sprintf("... (%d bytes total)", DefaultMaxLoggedBodyBytes+1)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, TruncateBody([]byte(tt.body), tt.max))
+		})
+	}
+}
+
+func TestRESTClient_GETPath(t *testing.T) {
+	server := createTestServer(t)
+	defer server.Close()
+
+	client, err := NewRESTClient(server.URL, AuthConfig{Type: NoAuth})
+	require.NoError(t, err)
+
+	ctx := context.Background()
+
+	t.Run("Successful substitution", func(t *testing.T) {
+		resp, err := client.GETPath(ctx, "/users/{id}", map[string]interface{}{"id": 1}, nil)
+
+		require.NoError(t, err)
+		assert.Equal(t, 200, resp.StatusCode)
+		assert.Equal(t, server.URL+"/users/1", resp.URL)
+
+		var user TestUser
+		require.NoError(t, resp.UnmarshalJSON(&user))
+		assert.Equal(t, "John Doe", user.Name)
+	})
+
+	t.Run("Missing parameter", func(t *testing.T) {
+		resp, err := client.GETPath(ctx, "/users/{id}/posts/{postId}", map[string]interface{}{"id": 1}, nil)
+
+		assert.Error(t, err)
+		assert.Nil(t, resp)
+		assert.Contains(t, err.Error(), "postId")
+	})
+}
+
+func TestExpandPath(t *testing.T) {
+	tests := []struct {
+		name        string
+		template    string
+		params      map[string]interface{}
+		expected    string
+		expectError string
+	}{
+		{
+			name:     "Typed parameters",
+			template: "/users/{id}/orders/{orderId}",
+			params:   map[string]interface{}{"id": 42, "orderId": int64(7)},
+			expected: "/users/42/orders/7",
+		},
+		{
+			name:     "Special characters escaped",
+			template: "/files/{name}",
+			params:   map[string]interface{}{"name": "a b/c?d"},
+			expected: "/files/a%20b%2Fc%3Fd",
+		},
+		{
+			name:     "Unicode escaped",
+			template: "/files/{name}",
+			params:   map[string]interface{}{"name": "résumé ✓.pdf"},
+			expected: "/files/r%C3%A9sum%C3%A9%20%E2%9C%93.pdf",
+		},
+		{
+			name:     "No placeholders",
+			template: "/users",
+			params:   nil,
+			expected: "/users",
+		},
+		{
+			name:        "Missing parameter",
+			template:    "/users/{id}",
+			params:      map[string]interface{}{"userId": 1},
+			expectError: "missing path parameters",
+		},
+		{
+			name:        "Leftover placeholder",
+			template:    "/users/{id",
+			params:      map[string]interface{}{"id": 1},
+			expectError: "unresolved placeholder",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := expandPath(tt.template, tt.params)
+
+			if tt.expectError != "" {
+				assert.Error(t, err)
+				assert.Contains(t, err.Error(), tt.expectError)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, result)
+		})
+	}
+}
+
+func TestPathSegments(t *testing.T) {
+	tests := []struct {
+		name        string
+		segment     string
+		escapedPath string
+	}{
+		{name: "Space", segment: "my file.txt", escapedPath: "/files/my%20file.txt"},
+		{name: "Slash", segment: "a/b", escapedPath: "/files/a%2Fb"},
+		{name: "Unicode", segment: "résumé.pdf", escapedPath: "/files/r%C3%A9sum%C3%A9.pdf"},
+		{name: "Query characters", segment: "what?#x", escapedPath: "/files/what%3F%23x"},
+	}
+
+	var receivedPath, receivedEscapedPath string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		receivedPath = r.URL.Path
+		receivedEscapedPath = r.URL.EscapedPath()
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	client, err := NewRESTClient(server.URL, AuthConfig{Type: NoAuth})
+	require.NoError(t, err)
+
+	ctx := context.Background()
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			endpoint := PathSegments("files", tt.segment)
+			assert.Equal(t, tt.escapedPath, endpoint)
+
+			_, err := client.DELETE(ctx, endpoint)
+			require.NoError(t, err)
+			assert.Equal(t, tt.escapedPath, receivedEscapedPath)
+			assert.Equal(t, "/files/"+tt.segment, receivedPath)
+
+			// Query parameters must not re-encode the escaped path
+			_, err = client.GET(ctx, endpoint, map[string]string{"version": "2"})
+			require.NoError(t, err)
+			assert.Equal(t, tt.escapedPath, receivedEscapedPath)
+
+			_, err = client.GETPath(ctx, "/files/{name}", map[string]interface{}{"name": tt.segment}, nil)
+			require.NoError(t, err)
+			assert.Equal(t, tt.escapedPath, receivedEscapedPath)
+		})
+	}
+}
+
+func TestRESTClient_Authentication(t *testing.T) {
+	server := createTestServer(t)
+	defer server.Close()
+
+	tests := []struct {
+		name           string
+		auth           AuthConfig
+		endpoint       string
+		expectedStatus int
+	}{
+		{
+			name: "Basic auth - success",
+			auth: AuthConfig{
+				Type:     BasicAuth,
+				Username: "testuser",
+				Password: "testpass",
+			},
+			endpoint:       "/auth/basic",
+			expectedStatus: 200,
+		},
+		{
+			name: "Basic auth - failure",
+			auth: AuthConfig{
+				Type:     BasicAuth,
+				Username: "wronguser",
+				Password: "wrongpass",
+			},
+			endpoint:       "/auth/basic",
+			expectedStatus: 401,
+		},
+		{
+			name: "Bearer auth - success",
+			auth: AuthConfig{
+				Type:  BearerAuth,
+				Token: "test-token-123",
+			},
+			endpoint:       "/auth/bearer",
+			expectedStatus: 200,
+		},
+		{
+			name: "Bearer auth - failure",
+			auth: AuthConfig{
+				Type:  BearerAuth,
+				Token: "wrong-token",
+			},
+			endpoint:       "/auth/bearer",
+			expectedStatus: 401,
+		},
+		{
+			name: "API Key auth - success",
+			auth: AuthConfig{
+				Type:   APIKeyAuth,
+				APIKey: "test-api-key-456",
+			},
+			endpoint:       "/auth/apikey",
+			expectedStatus: 200,
+		},
+		{
+			name: "API Key auth - failure",
+			auth: AuthConfig{
+				Type:   APIKeyAuth,
+				APIKey: "wrong-api-key",
+			},
+			endpoint:       "/auth/apikey",
+			expectedStatus: 401,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client, err := NewRESTClient(server.URL, tt.auth)
+			require.NoError(t, err)
+
+			ctx := context.Background()
+			resp, err := client.GET(ctx, tt.endpoint, nil)
+
+			assert.NoError(t, err)
+			assert.NotNil(t, resp)
+			assert.Equal(t, tt.expectedStatus, resp.StatusCode)
+		})
+	}
+}
+
+func TestRESTClient_BasicAuthCredentialProvider(t *testing.T) {
+	type credentials struct{ username, password string }
+
+	var received []credentials
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		username, password, ok := r.BasicAuth()
+		assert.True(t, ok)
+		received = append(received, credentials{username, password})
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	calls := 0
+	client, err := NewRESTClient(server.URL, AuthConfig{
+		Type: BasicAuth,
+		CredentialProvider: func(ctx context.Context) (string, string, error) {
+			calls++
+			return fmt.Sprintf("user-%d", calls), fmt.Sprintf("secret-%d", calls), nil
+		},
+	})
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	for i := 0; i < 2; i++ {
+		resp, err := client.GET(ctx, "/users", nil)
+		require.NoError(t, err)
+		assert.Equal(t, 200, resp.StatusCode)
+	}
+
+	assert.Equal(t, []credentials{{"user-1", "secret-1"}, {"user-2", "secret-2"}}, received)
+
+	t.Run("Provider error", func(t *testing.T) {
+		client, err := NewRESTClient(server.URL, AuthConfig{
+			Type: BasicAuth,
+			CredentialProvider: func(ctx context.Context) (string, string, error) {
+				return "", "", errors.New("vault sealed")
+			},
+		})
+		require.NoError(t, err)
+
+		_, err = client.GET(ctx, "/users", nil)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "vault sealed")
+	})
+}
+
+func TestRESTClient_BearerTokenProvider(t *testing.T) {
+	var received []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received = append(received, r.Header.Get("Authorization"))
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	issued := 0
+	client, err := NewRESTClient(server.URL, AuthConfig{
+		Type:  BearerAuth,
+		Token: "static-token",
+		TokenProvider: func(ctx context.Context) (string, error) {
+			issued++
+			return fmt.Sprintf("fresh-token-%d", issued), nil
+		},
+	})
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	for i := 0; i < 2; i++ {
+		resp, err := client.GET(ctx, "/users", nil)
+		require.NoError(t, err)
+		assert.Equal(t, 200, resp.StatusCode)
+	}
+
+	assert.Equal(t, []string{"Bearer fresh-token-1", "Bearer fresh-token-2"}, received)
+
+	t.Run("Provider error", func(t *testing.T) {
+		client, err := NewRESTClient(server.URL, AuthConfig{
+			Type: BearerAuth,
+			TokenProvider: func(ctx context.Context) (string, error) {
+				return "", errors.New("identity provider unavailable")
+			},
+		})
+		require.NoError(t, err)
+
+		_, err = client.GET(ctx, "/users", nil)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "identity provider unavailable")
+	})
+
+	t.Run("Empty token", func(t *testing.T) {
+		client, err := NewRESTClient(server.URL, AuthConfig{
+			Type: BearerAuth,
+			TokenProvider: func(ctx context.Context) (string, error) {
+				return "", nil
+			},
+		})
+		require.NoError(t, err)
+
+		_, err = client.GET(ctx, "/users", nil)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "bearer auth requires token")
+	})
+}
+
+func TestRESTClient_OAuth2TokenError(t *testing.T) {
+	tokenServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusUnauthorized)
+		w.Write([]byte(`{"error":"invalid_client","error_description":"Client authentication failed"}`))
+	}))
+	defer tokenServer.Close()
+
+	apiServer := createTestServer(t)
+	defer apiServer.Close()
+
+	tokenURL := tokenServer.URL + "/oauth/token"
+	client, err := NewRESTClient(apiServer.URL, AuthConfig{
+		Type:         OAuth2Auth,
+		ClientID:     "client-id",
+		ClientSecret: "wrong-secret",
+		TokenURL:     tokenURL,
+	})
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	resp, err := client.GET(ctx, "/users/1", nil)
+
+	assert.Nil(t, resp)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid_client")
+	assert.Contains(t, err.Error(), "Client authentication failed")
+	assert.Contains(t, err.Error(), tokenURL)
+
+	var retrieveErr *oauth2.RetrieveError
+	assert.True(t, errors.As(err, &retrieveErr))
+}
+
+func TestRESTClient_OAuth2RefreshOn401(t *testing.T) {
+	tokensIssued := 0
+	tokenServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		tokensIssued++
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		fmt.Fprintf(w, `{"access_token":"token-%d","token_type":"Bearer","expires_in":3600}`, tokensIssued)
+	}))
+	defer tokenServer.Close()
+
+	t.Run("expired token is refreshed and the request retried", func(t *testing.T) {
+		tokensIssued = 0
+		apiCalls := 0
+		apiServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			apiCalls++
+			// The first token is treated as expired
+			if r.Header.Get("Authorization") != "Bearer token-2" {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			body, _ := io.ReadAll(r.Body)
+			assert.JSONEq(t, `{"name":"John Doe"}`, string(body))
+
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusOK)
+			w.Write([]byte(`{"id":1,"name":"John Doe"}`))
+		}))
+		defer apiServer.Close()
+
+		client, err := NewRESTClient(apiServer.URL, AuthConfig{
+			Type:         OAuth2Auth,
+			ClientID:     "client-id",
+			ClientSecret: "client-secret",
+			TokenURL:     tokenServer.URL,
+		})
+		require.NoError(t, err)
+
+		resp, err := client.POST(context.Background(), "/users", map[string]string{"name": "John Doe"})
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Equal(t, 2, apiCalls)
+		assert.Equal(t, 2, tokensIssued)
+	})
+
+	t.Run("retries only once", func(t *testing.T) {
+		tokensIssued = 0
+		apiCalls := 0
+		apiServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			apiCalls++
+			w.WriteHeader(http.StatusUnauthorized)
+		}))
+		defer apiServer.Close()
+
+		client, err := NewRESTClient(apiServer.URL, AuthConfig{
+			Type:         OAuth2Auth,
+			ClientID:     "client-id",
+			ClientSecret: "client-secret",
+			TokenURL:     tokenServer.URL,
+		})
+		require.NoError(t, err)
+
+		resp, err := client.GET(context.Background(), "/users/1", nil)
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+		assert.Equal(t, 2, apiCalls)
+		assert.Equal(t, 2, tokensIssued)
+	})
+}
+
+func TestRESTClient_OAuth2Grants(t *testing.T) {
+	var mu sync.Mutex
+	var tokenRequests []url.Values
+	tokenServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.NoError(t, r.ParseForm())
+		mu.Lock()
+		tokenRequests = append(tokenRequests, r.PostForm)
+		issued := len(tokenRequests)
+		mu.Unlock()
+
+		w.Header().Set("Content-Type", "application/json")
+		switch r.PostForm.Get("grant_type") {
+		case "password":
+			if r.PostForm.Get("username") != "alice" || r.PostForm.Get("password") != "wonderland" {
+				w.WriteHeader(http.StatusBadRequest)
+				w.Write([]byte(`{"error":"invalid_grant"}`))
+				return
+			}
+			fmt.Fprintf(w, `{"access_token":"access-%d","token_type":"Bearer","expires_in":3600}`, issued)
+		case "refresh_token":
+			// Rotate the refresh token on every use
+			fmt.Fprintf(w, `{"access_token":"access-%d","token_type":"Bearer","expires_in":3600,"refresh_token":"refresh-%d"}`, issued, issued+1)
+		default:
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"error":"unsupported_grant_type"}`))
+		}
+	}))
+	defer tokenServer.Close()
+
+	var authorizations []string
+	apiServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		authorizations = append(authorizations, r.Header.Get("Authorization"))
+		mu.Unlock()
+		// The first access token is treated as expired
+		if r.Header.Get("Authorization") == "Bearer access-1" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer apiServer.Close()
+
+	reset := func() {
+		mu.Lock()
+		defer mu.Unlock()
+		tokenRequests = nil
+		authorizations = nil
+	}
+
+	t.Run("Password grant", func(t *testing.T) {
+		reset()
+		client, err := NewRESTClient(apiServer.URL, AuthConfig{
+			Type:        OAuth2Auth,
+			OAuth2Grant: OAuth2Password,
+			ClientID:    "legacy-client",
+			TokenURL:    tokenServer.URL,
+			Username:    "alice",
+			Password:    "wonderland",
+			Scopes:      []string{"read"},
+		})
+		require.NoError(t, err)
+
+		resp, err := client.GET(context.Background(), "/users/1", nil)
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+		require.Len(t, tokenRequests, 2, "the expired token is replaced with a new password grant")
+		for _, form := range tokenRequests {
+			assert.Equal(t, "password", form.Get("grant_type"))
+			assert.Equal(t, "alice", form.Get("username"))
+			assert.Equal(t, "read", form.Get("scope"))
+		}
+		assert.Equal(t, []string{"Bearer access-1", "Bearer access-2"}, authorizations)
+	})
+
+	t.Run("Refresh token grant", func(t *testing.T) {
+		reset()
+		client, err := NewRESTClient(apiServer.URL, AuthConfig{
+			Type:         OAuth2Auth,
+			OAuth2Grant:  OAuth2RefreshToken,
+			ClientID:     "client-id",
+			ClientSecret: "client-secret",
+			TokenURL:     tokenServer.URL,
+			RefreshToken: "refresh-1",
+		})
+		require.NoError(t, err)
+
+		resp, err := client.GET(context.Background(), "/users/1", nil)
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+		require.Len(t, tokenRequests, 2)
+		assert.Equal(t, "refresh_token", tokenRequests[0].Get("grant_type"))
+		assert.Equal(t, "refresh-1", tokenRequests[0].Get("refresh_token"))
+		assert.Equal(t, "refresh-2", tokenRequests[1].Get("refresh_token"), "the rotated refresh token is used")
+		assert.Equal(t, []string{"Bearer access-1", "Bearer access-2"}, authorizations)
+	})
+
+	t.Run("Invalid configuration", func(t *testing.T) {
+		tests := []struct {
+			name        string
+			auth        AuthConfig
+			expectError string
+		}{
+			{
+				name:        "Password grant without username",
+				auth:        AuthConfig{Type: OAuth2Auth, OAuth2Grant: OAuth2Password, ClientID: "id", TokenURL: tokenServer.URL},
+				expectError: "password grant requires",
+			},
+			{
+				name:        "Refresh grant without refresh token",
+				auth:        AuthConfig{Type: OAuth2Auth, OAuth2Grant: OAuth2RefreshToken, ClientID: "id", TokenURL: tokenServer.URL},
+				expectError: "refresh_token grant requires",
+			},
+			{
+				name:        "Unknown grant",
+				auth:        AuthConfig{Type: OAuth2Auth, OAuth2Grant: "implicit", ClientID: "id", TokenURL: tokenServer.URL},
+				expectError: "unsupported OAuth2 grant",
+			},
+		}
+
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				client, err := NewRESTClient(apiServer.URL, tt.auth)
+				assert.Nil(t, client)
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.expectError)
+			})
+		}
+	})
+}
+
+func TestRESTClient_Timeout(t *testing.T) {
+	server := createTestServer(t)
+	defer server.Close()
+
+	client, err := NewRESTClient(server.URL, AuthConfig{Type: NoAuth})
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	req := RESTRequest{
+		Method:   GET,
+		Endpoint: "/delay",
+		Timeout:  1 * time.Second, // Shorter than server delay
+	}
+
+	start := time.Now()
+	resp, err := client.Execute(ctx, req)
+	duration := time.Since(start)
+
+	assert.Error(t, err)
+	assert.Nil(t, resp)
+	assert.True(t, duration < 2*time.Second, "Request should have timed out before 2 seconds")
+}
+
+func TestNewRESTClientWithTimeout(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(300 * time.Millisecond)
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(`{"message":"delayed response"}`))
+	}))
+	defer server.Close()
+
+	client, err := NewRESTClientWithTimeout(server.URL, AuthConfig{Type: NoAuth}, 100*time.Millisecond)
+	require.NoError(t, err)
+	assert.Equal(t, 100*time.Millisecond, client.HTTPClient().Timeout)
+
+	ctx := context.Background()
+
+	t.Run("Client-wide default applies", func(t *testing.T) {
+		resp, err := client.GET(ctx, "/delay", nil)
+
+		assert.Error(t, err)
+		assert.Nil(t, resp)
+	})
+
+	t.Run("Per-request timeout wins", func(t *testing.T) {
+		resp, err := client.Execute(ctx, RESTRequest{
+			Method:   GET,
+			Endpoint: "/delay",
+			Timeout:  2 * time.Second,
+		})
+
+		assert.NoError(t, err)
+		require.NotNil(t, resp)
+		assert.Equal(t, 200, resp.StatusCode)
+	})
+}
+
+func TestNewRESTClientWithOptions(t *testing.T) {
+	var received http.Header
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received = r.Header.Clone()
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	ctx := context.Background()
+
+	t.Run("Custom user agent", func(t *testing.T) {
+		client, err := NewRESTClientWithOptions(server.URL, AuthConfig{Type: NoAuth}, Options{UserAgent: "billing-service/2.3"})
+		require.NoError(t, err)
+
+		_, err = client.GET(ctx, "/users", nil)
+		require.NoError(t, err)
+
+		assert.Equal(t, "billing-service/2.3", received.Get("User-Agent"))
+		assert.Equal(t, "application/json", received.Get("Accept"))
+	})
+
+	t.Run("Default headers disabled", func(t *testing.T) {
+		client, err := NewRESTClientWithOptions(server.URL, AuthConfig{Type: NoAuth}, Options{DisableDefaultHeaders: true})
+		require.NoError(t, err)
+
+		_, err = client.POST(ctx, "/users", TestUser{Name: "Alice"})
+		require.NoError(t, err)
+
+		assert.Empty(t, received.Get("Content-Type"))
+		assert.Empty(t, received.Get("Accept"))
+		assert.NotEqual(t, "RESTClient/1.0", received.Get("User-Agent"))
+
+		_, err = client.Execute(ctx, RESTRequest{
+			Method:   POST,
+			Endpoint: "/users",
+			Body:     TestUser{Name: "Alice"},
+			Headers:  map[string]string{"Content-Type": "application/json"},
+		})
+		require.NoError(t, err)
+
+		assert.Equal(t, "application/json", received.Get("Content-Type"))
+		assert.Empty(t, received.Get("Accept"))
+	})
+}
+
+func TestRESTClient_HTTPClient(t *testing.T) {
+	server := createTestServer(t)
+	defer server.Close()
+
+	client, err := NewRESTClient(server.URL, AuthConfig{Type: NoAuth})
+	require.NoError(t, err)
+
+	httpClient := client.HTTPClient()
+	assert.Same(t, client.httpClient, httpClient)
+	assert.Same(t, httpClient, client.selectHTTPClient(0))
+
+	// Shorter than server delay
+	httpClient.Timeout = 500 * time.Millisecond
+
+	ctx := context.Background()
+	start := time.Now()
+	resp, err := client.GET(ctx, "/delay", nil)
+	duration := time.Since(start)
+
+	assert.Error(t, err)
+	assert.Nil(t, resp)
+	assert.True(t, duration < 2*time.Second, "Request should have used the modified timeout")
+}
+
+func TestRESTClient_DisableKeepAlives(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.True(t, r.Close, "Request should carry Connection: close")
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"message":"ok"}`))
+	}))
+	defer server.Close()
+
+	client, err := NewRESTClient(server.URL, AuthConfig{Type: NoAuth})
+	require.NoError(t, err)
+	client.DisableKeepAlives()
+
+	var reused []bool
+	trace := &httptrace.ClientTrace{
+		GotConn: func(info httptrace.GotConnInfo) {
+			reused = append(reused, info.Reused)
+		},
+	}
+	ctx := httptrace.WithClientTrace(context.Background(), trace)
+
+	for i := 0; i < 3; i++ {
+		resp, err := client.GET(ctx, "/ping", nil)
+		require.NoError(t, err)
+		assert.Equal(t, 200, resp.StatusCode)
+	}
+
+	assert.Equal(t, []bool{false, false, false}, reused)
+}
+
+func TestRESTClient_HTTP2(t *testing.T) {
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]string{"proto": r.Proto})
+	}))
+	server.EnableHTTP2 = true
+	server.StartTLS()
+	defer server.Close()
+
+	roots := x509.NewCertPool()
+	roots.AddCert(server.Certificate())
+
+	// A custom TLS config is what normally stops Go from attempting HTTP/2
+	trustServer := func(t *testing.T, client *RESTClient) {
+		transport, ok := client.HTTPClient().Transport.(*http.Transport)
+		require.True(t, ok)
+		transport.TLSClientConfig = &tls.Config{RootCAs: roots}
+	}
+	protocol := func(t *testing.T, client *RESTClient) string {
+		resp, err := client.GET(context.Background(), "/proto", nil)
+		require.NoError(t, err)
+		var body map[string]string
+		require.NoError(t, resp.UnmarshalJSON(&body))
+		return body["proto"]
+	}
+
+	t.Run("Forced", func(t *testing.T) {
+		client, err := NewRESTClientWithOptions(server.URL, AuthConfig{Type: NoAuth}, Options{ForceHTTP2: true})
+		require.NoError(t, err)
+		trustServer(t, client)
+
+		assert.Equal(t, "HTTP/2.0", protocol(t, client))
+	})
+
+	t.Run("Disabled", func(t *testing.T) {
+		client, err := NewRESTClientWithOptions(server.URL, AuthConfig{Type: NoAuth}, Options{DisableHTTP2: true})
+		require.NoError(t, err)
+		trustServer(t, client)
+
+		assert.Equal(t, "HTTP/1.1", protocol(t, client))
+	})
+
+	t.Run("Force after disable re-enables", func(t *testing.T) {
+		client, err := NewRESTClient(server.URL, AuthConfig{Type: NoAuth})
+		require.NoError(t, err)
+		client.DisableHTTP2().ForceHTTP2()
+		trustServer(t, client)
+
+		assert.Equal(t, "HTTP/2.0", protocol(t, client))
+	})
+
+	t.Run("Both options", func(t *testing.T) {
+		_, err := NewRESTClientWithOptions(server.URL, AuthConfig{Type: NoAuth}, Options{ForceHTTP2: true, DisableHTTP2: true})
+		assert.Error(t, err)
+	})
+}
+
+func TestRESTClient_Close(t *testing.T) {
+	var closed int32
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Path == "/token" {
+			w.Write([]byte(`{"access_token":"token","token_type":"Bearer","expires_in":3600}`))
+			return
+		}
+		w.Write([]byte(`{"message":"ok"}`))
+	}))
+	server.Config.ConnState = func(conn net.Conn, state http.ConnState) {
+		if state == http.StateClosed {
+			atomic.AddInt32(&closed, 1)
+		}
+	}
+	server.Start()
+	defer server.Close()
+
+	tests := []struct {
+		name string
+		auth AuthConfig
+	}{
+		{name: "Plain", auth: AuthConfig{Type: NoAuth}},
+		{name: "OAuth2", auth: AuthConfig{
+			Type:         OAuth2Auth,
+			ClientID:     "client",
+			ClientSecret: "secret",
+			TokenURL:     server.URL + "/token",
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client, err := NewRESTClient(server.URL, tt.auth)
+			require.NoError(t, err)
+			// Use a dedicated transport so the connection pool is the client's own
+			client.WithTransportTimeouts(TransportTimeouts{DialTimeout: 5 * time.Second})
+
+			var reused []bool
+			trace := &httptrace.ClientTrace{
+				GotConn: func(info httptrace.GotConnInfo) {
+					reused = append(reused, info.Reused)
+				},
+			}
+			ctx := httptrace.WithClientTrace(context.Background(), trace)
+
+			for i := 0; i < 2; i++ {
+				_, err := client.GET(ctx, "/ping", nil)
+				require.NoError(t, err)
+			}
+			require.Equal(t, []bool{false, true}, reused, "the idle connection should be reused before Close")
+
+			before := atomic.LoadInt32(&closed)
+			client.Close()
+
+			assert.Eventually(t, func() bool {
+				return atomic.LoadInt32(&closed) > before
+			}, time.Second, 10*time.Millisecond, "server should see the idle connection closed")
+
+			// The client stays usable but must dial a fresh connection
+			_, err = client.GET(ctx, "/ping", nil)
+			require.NoError(t, err)
+			assert.Equal(t, []bool{false, true, false}, reused)
+		})
+	}
+}
+
+func TestRESTClient_RateLimitPriority(t *testing.T) {
+	var mu sync.Mutex
+	var order []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		order = append(order, r.URL.Query().Get("name"))
+		mu.Unlock()
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	client, err := NewRESTClientWithOptions(server.URL, AuthConfig{Type: NoAuth}, Options{
+		RateLimit: &RateLimit{RequestsPerSecond: 20, Burst: 1},
+	})
+	require.NoError(t, err)
+
+	send := func(name string, priority Priority) {
+		_, err := client.Execute(context.Background(), RESTRequest{
+			Method:      GET,
+			Endpoint:    "/items",
+			QueryParams: map[string]string{"name": name},
+			Priority:    priority,
+		})
+		assert.NoError(t, err)
+	}
+
+	// The first request takes the only token; the rest queue behind it
+	send("normal-0", PriorityNormal)
+
+	var wg sync.WaitGroup
+	for i := 1; i <= 3; i++ {
+		wg.Add(1)
+		go func(name string) {
+			defer wg.Done()
+			send(name, "")
+		}(fmt.Sprintf("normal-%d", i))
+	}
+	queued := func() int {
+		client.limiter.mu.Lock()
+		defer client.limiter.mu.Unlock()
+		return len(client.limiter.normal)
+	}
+	require.Eventually(t, func() bool { return queued() == 3 }, time.Second, time.Millisecond)
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		send("high", PriorityHigh)
+	}()
+	wg.Wait()
+
+	require.Len(t, order, 5)
+	assert.Equal(t, "normal-0", order[0])
+	assert.Equal(t, "high", order[1], "high priority request should jump the queue")
+	assert.ElementsMatch(t, []string{"normal-1", "normal-2", "normal-3"}, order[2:])
+
+	t.Run("Cancelled wait", func(t *testing.T) {
+		limited, err := NewRESTClient(server.URL, AuthConfig{Type: NoAuth})
+		require.NoError(t, err)
+		limited.WithRateLimit(RateLimit{RequestsPerSecond: 0.1})
+
+		_, err = limited.GET(context.Background(), "/items", nil)
+		require.NoError(t, err)
+
+		ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+		defer cancel()
+		_, err = limited.GET(ctx, "/items", nil)
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+		limited.limiter.mu.Lock()
+		defer limited.limiter.mu.Unlock()
+		assert.Empty(t, limited.limiter.normal, "cancelled waiters leave the queue")
+	})
+}
+
+func TestRESTClient_RateLimitSpacing(t *testing.T) {
+	const (
+		rate     = 20.0
+		requests = 10
+	)
+
+	var mu sync.Mutex
+	var arrivals []time.Time
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		arrivals = append(arrivals, time.Now())
+		mu.Unlock()
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	client, err := NewRESTClient(server.URL, AuthConfig{Type: NoAuth})
+	require.NoError(t, err)
+	client.WithRateLimit(RateLimit{RequestsPerSecond: rate, Burst: 1})
+
+	// Saturate the limiter so every request after the first waits for a refill
+	var wg sync.WaitGroup
+	for i := 0; i < requests; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := client.GET(context.Background(), "/items", nil)
+			assert.NoError(t, err)
+		}()
+	}
+	wg.Wait()
+
+	require.Len(t, arrivals, requests)
+	sort.Slice(arrivals, func(i, j int) bool { return arrivals[i].Before(arrivals[j]) })
+
+	interval := time.Duration(float64(time.Second) / rate)
+	for i := 1; i < len(arrivals); i++ {
+		gap := arrivals[i].Sub(arrivals[i-1])
+		assert.Greater(t, gap, interval/2, "request %d followed the previous one after %v", i, gap)
+	}
+
+	// A per-second refill would send all requests at once; a continuous one spreads them evenly
+	average := arrivals[len(arrivals)-1].Sub(arrivals[0]) / time.Duration(requests-1)
+	assert.InDelta(t, float64(interval), float64(average), float64(interval)/4)
+}
+
+func TestRESTClient_WithLocalAddr(t *testing.T) {
+	var remoteAddr string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		remoteAddr = r.RemoteAddr
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"message":"ok"}`))
+	}))
+	defer server.Close()
+
+	t.Run("Connection originates from local address", func(t *testing.T) {
+		client, err := NewRESTClient(server.URL, AuthConfig{Type: NoAuth})
+		require.NoError(t, err)
+
+		// Any 127.0.0.0/8 address is loopback on Linux, so this differs from the server's 127.0.0.1
+		_, err = client.WithLocalAddr("127.0.0.2")
+		require.NoError(t, err)
+
+		resp, err := client.GET(context.Background(), "/ping", nil)
+		if err != nil {
+			t.Skipf("cannot bind to 127.0.0.2 on this host: %v", err)
+		}
+		assert.Equal(t, 200, resp.StatusCode)
+
+		host, _, err := net.SplitHostPort(remoteAddr)
+		require.NoError(t, err)
+		assert.Equal(t, "127.0.0.2", host)
+	})
+
+	t.Run("Composes with DisableKeepAlives", func(t *testing.T) {
+		client, err := NewRESTClient(server.URL, AuthConfig{Type: NoAuth})
+		require.NoError(t, err)
+
+		_, err = client.DisableKeepAlives().WithLocalAddr("127.0.0.1:0")
+		require.NoError(t, err)
+
+		transport, ok := client.HTTPClient().Transport.(*http.Transport)
+		require.True(t, ok)
+		assert.True(t, transport.DisableKeepAlives)
+		assert.NotNil(t, transport.DialContext)
+	})
+
+	t.Run("Invalid address", func(t *testing.T) {
+		client, err := NewRESTClient(server.URL, AuthConfig{Type: NoAuth})
+		require.NoError(t, err)
+
+		_, err = client.WithLocalAddr("not-an-ip")
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "invalid local address")
+	})
+}
+
+func TestRESTClient_WithTransportTimeouts(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/slow-headers" {
+			time.Sleep(200 * time.Millisecond)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		w.(http.Flusher).Flush()
+
+		if r.URL.Path == "/slow-body" {
+			time.Sleep(200 * time.Millisecond)
+		}
+		w.Write([]byte(`{"message":"ok"}`))
+	}))
+	defer server.Close()
+
+	timeouts := TransportTimeouts{
+		DialTimeout:           time.Second,
+		TLSHandshakeTimeout:   time.Second,
+		ResponseHeaderTimeout: 50 * time.Millisecond,
+	}
+	ctx := context.Background()
+
+	t.Run("Slow headers trip the response header timeout", func(t *testing.T) {
+		client, err := NewRESTClientWithTimeout(server.URL, AuthConfig{Type: NoAuth}, 0)
+		require.NoError(t, err)
+		client.WithTransportTimeouts(timeouts)
+
+		_, err = client.GET(ctx, "/slow-headers", nil)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "timeout awaiting response headers")
+	})
+
+	t.Run("Slow body does not trip the response header timeout", func(t *testing.T) {
+		client, err := NewRESTClientWithTimeout(server.URL, AuthConfig{Type: NoAuth}, 0)
+		require.NoError(t, err)
+		client.WithTransportTimeouts(timeouts)
+
+		resp, err := client.GET(ctx, "/slow-body", nil)
+		require.NoError(t, err)
+		assert.Equal(t, 200, resp.StatusCode)
+		assert.Equal(t, `{"message":"ok"}`, resp.String())
+	})
+
+	t.Run("Slow body trips a short overall timeout", func(t *testing.T) {
+		client, err := NewRESTClientWithTimeout(server.URL, AuthConfig{Type: NoAuth}, 100*time.Millisecond)
+		require.NoError(t, err)
+		client.WithTransportTimeouts(timeouts)
+
+		_, err = client.GET(ctx, "/slow-body", nil)
+		assert.Error(t, err)
+	})
+
+	t.Run("Composes with WithLocalAddr", func(t *testing.T) {
+		client, err := NewRESTClient(server.URL, AuthConfig{Type: NoAuth})
+		require.NoError(t, err)
+
+		_, err = client.WithTransportTimeouts(timeouts).WithLocalAddr("127.0.0.1")
+		require.NoError(t, err)
+		assert.Equal(t, time.Second, client.dialer.Timeout)
+		assert.NotNil(t, client.dialer.LocalAddr)
+
+		transport, ok := client.HTTPClient().Transport.(*http.Transport)
+		require.True(t, ok)
+		assert.Equal(t, 50*time.Millisecond, transport.ResponseHeaderTimeout)
+	})
+}
+
+// capturingHandler records slog records for assertions
+type capturingHandler struct {
+	records []slog.Record
+}
+
+func (h *capturingHandler) Enabled(context.Context, slog.Level) bool { return true }
+
+func (h *capturingHandler) Handle(_ context.Context, r slog.Record) error {
+	h.records = append(h.records, r)
+	return nil
+}
+
+func (h *capturingHandler) WithAttrs([]slog.Attr) slog.Handler { return h }
+func (h *capturingHandler) WithGroup(string) slog.Handler      { return h }
+
+// attrs flattens a record's attributes into a map
+func (h *capturingHandler) attrs(r slog.Record) map[string]string {
+	attrs := make(map[string]string)
+	r.Attrs(func(a slog.Attr) bool {
+		attrs[a.Key] = a.Value.String()
+		return true
+	})
+	return attrs
+}
+
+func TestRESTClient_Logger(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"message":"ok"}`))
+	}))
+	defer server.Close()
+
+	client, err := NewRESTClient(server.URL, AuthConfig{
+		Type:     APIKeyAuth,
+		APIKey:   "super-secret-key",
+		KeyQuery: "api_key",
+	})
+	require.NoError(t, err)
+
+	handler := &capturingHandler{}
+	client.Logger = slog.New(handler)
+
+	ctx := context.Background()
+
+	t.Run("Request and response", func(t *testing.T) {
+		handler.records = nil
+
+		_, err := client.GET(ctx, "/users/1", map[string]string{"expand": "profile"})
+		require.NoError(t, err)
+		require.Len(t, handler.records, 2)
+
+		request := handler.records[0]
+		assert.Equal(t, slog.LevelDebug, request.Level)
+		attrs := handler.attrs(request)
+		assert.Equal(t, "GET", attrs["method"])
+		assert.Equal(t, server.URL+"/users/1?expand=profile", attrs["url"])
+
+		response := handler.records[1]
+		assert.Equal(t, slog.LevelInfo, response.Level)
+		attrs = handler.attrs(response)
+		assert.Equal(t, "200", attrs["status"])
+		assert.Contains(t, attrs, "duration")
+	})
+
+	t.Run("Error", func(t *testing.T) {
+		handler.records = nil
+
+		_, err := client.Execute(ctx, RESTRequest{
+			Method:   GET,
+			BaseURL:  "http://127.0.0.1:1",
+			Endpoint: "/unreachable",
+		})
+		require.Error(t, err)
+		require.Len(t, handler.records, 2)
+
+		failure := handler.records[1]
+		assert.Equal(t, slog.LevelError, failure.Level)
+		assert.Contains(t, handler.attrs(failure)["error"], "failed to execute HTTP request")
+	})
+
+	t.Run("Secrets are not logged", func(t *testing.T) {
+		for _, record := range handler.records {
+			assert.NotContains(t, record.Message, "super-secret-key")
+			for _, value := range handler.attrs(record) {
+				assert.NotContains(t, value, "super-secret-key")
+			}
+		}
+	})
+}
+
+func TestRESTClient_TracePropagation(t *testing.T) {
+	traceparentPattern := regexp.MustCompile(`^00-[0-9a-f]{32}-[0-9a-f]{16}-0[0-9a-f]$`)
+
+	var traceparent, tracestate string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		traceparent = r.Header.Get("traceparent")
+		tracestate = r.Header.Get("tracestate")
+		w.Header().Set("Server-Timing", `db;dur=53, app;dur=47.2`)
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"message":"ok"}`))
+	}))
+	defer server.Close()
+
+	client, err := NewRESTClient(server.URL, AuthConfig{Type: NoAuth})
+	require.NoError(t, err)
+	client.EnableTracePropagation()
+
+	traceID, err := trace.TraceIDFromHex("4bf92f3577b34da6a3ce929d0e0e4736")
+	require.NoError(t, err)
+	spanID, err := trace.SpanIDFromHex("00f067aa0ba902b7")
+	require.NoError(t, err)
+	traceState, err := trace.ParseTraceState("vendor=value")
+	require.NoError(t, err)
+
+	ctx := trace.ContextWithSpanContext(context.Background(), trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID:    traceID,
+		SpanID:     spanID,
+		TraceFlags: trace.FlagsSampled,
+		TraceState: traceState,
+	}))
+
+	resp, err := client.GET(ctx, "/traced", nil)
+	require.NoError(t, err)
+
+	assert.Regexp(t, traceparentPattern, traceparent)
+	assert.Equal(t, "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01", traceparent)
+	assert.Equal(t, "vendor=value", tracestate)
+	assert.Equal(t, `db;dur=53, app;dur=47.2`, resp.ServerTiming)
+
+	t.Run("No span on context", func(t *testing.T) {
+		_, err := client.GET(context.Background(), "/traced", nil)
+		require.NoError(t, err)
+		assert.Empty(t, traceparent)
+	})
+}
+
+func TestRESTClient_BuildRequest(t *testing.T) {
+	requestSent := false
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requestSent = true
+	}))
+	defer server.Close()
+
+	client, err := NewRESTClient(server.URL, AuthConfig{Type: BearerAuth, Token: "secret-token"})
+	require.NoError(t, err)
+
+	httpReq, err := client.BuildRequest(context.Background(), RESTRequest{
+		Method:      POST,
+		Endpoint:    "/users",
+		QueryParams: map[string]string{"notify": "true"},
+		Body:        TestUser{Name: "Alice", Email: "alice@example.com"},
+	})
+	require.NoError(t, err)
+
+	assert.False(t, requestSent, "BuildRequest should not send the request")
+	assert.Equal(t, "POST", httpReq.Method)
+	assert.Equal(t, server.URL+"/users?notify=true", httpReq.URL.String())
+	assert.Equal(t, "Bearer secret-token", httpReq.Header.Get("Authorization"))
+	assert.Equal(t, "application/json", httpReq.Header.Get("Content-Type"))
+
+	body, err := io.ReadAll(httpReq.Body)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"id":0,"name":"Alice","email":"alice@example.com"}`, string(body))
+}
+
+func TestRESTClient_CurlCommand(t *testing.T) {
+	client, err := NewRESTClient("https://api.example.com", AuthConfig{Type: BearerAuth, Token: "secret-token"})
+	require.NoError(t, err)
+
+	ctx := context.Background()
+
+	t.Run("POST with JSON body and bearer auth", func(t *testing.T) {
+		curl, err := client.CurlCommand(ctx, RESTRequest{
+			Method:   POST,
+			Endpoint: "/users",
+			Body:     TestUser{Name: "O'Brien", Email: "obrien@example.com"},
+		})
+		require.NoError(t, err)
+
+		expected := `curl -X POST 'https://api.example.com/users'` +
+			` -H 'Accept: application/json'` +
+			` -H 'Authorization: [REDACTED]'` +
+			` -H 'Content-Type: application/json'` +
+			` -H 'User-Agent: RESTClient/1.0'` +
+			` --data-raw '{"id":0,"name":"O'\''Brien","email":"obrien@example.com"}'`
+		assert.Equal(t, expected, curl)
+		assert.NotContains(t, curl, "secret-token")
+	})
+
+	t.Run("API key in query is redacted", func(t *testing.T) {
+		keyClient, err := NewRESTClient("https://api.example.com", AuthConfig{
+			Type:     APIKeyAuth,
+			APIKey:   "secret-key",
+			KeyQuery: "api_key",
+		})
+		require.NoError(t, err)
+
+		curl, err := keyClient.CurlCommand(ctx, RESTRequest{Method: GET, Endpoint: "/users"})
+		require.NoError(t, err)
+
+		assert.Contains(t, curl, `'https://api.example.com/users?api_key=[REDACTED]'`)
+		assert.NotContains(t, curl, "secret-key")
+		assert.NotContains(t, curl, "--data-raw")
+	})
+}
+
+func TestRESTClient_ExtraAuthHeaders(t *testing.T) {
+	var received http.Header
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received = r.Header.Clone()
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	ctx := context.Background()
+
+	t.Run("API key with companion secret header", func(t *testing.T) {
+		client, err := NewRESTClient(server.URL, AuthConfig{
+			Type:             APIKeyAuth,
+			APIKey:           "test-api-key-456",
+			KeyHeader:        "X-API-Key",
+			ExtraAuthHeaders: map[string]string{"X-API-Secret": "s3cret"},
+		})
+		require.NoError(t, err)
+
+		resp, err := client.GET(ctx, "/resource", nil)
+		require.NoError(t, err)
+		assert.Equal(t, 200, resp.StatusCode)
+		assert.Equal(t, "test-api-key-456", received.Get("X-API-Key"))
+		assert.Equal(t, "s3cret", received.Get("X-API-Secret"))
+
+		curl, err := client.CurlCommand(ctx, RESTRequest{Method: GET, Endpoint: "/resource"})
+		require.NoError(t, err)
+		assert.Contains(t, curl, `-H 'X-Api-Secret: [REDACTED]'`)
+		assert.NotContains(t, curl, "s3cret")
+	})
+
+	t.Run("applied without an auth type", func(t *testing.T) {
+		client, err := NewRESTClient(server.URL, AuthConfig{
+			Type:             NoAuth,
+			ExtraAuthHeaders: map[string]string{"X-Tenant-Token": "tenant-1"},
+		})
+		require.NoError(t, err)
+
+		_, err = client.GET(ctx, "/resource", nil)
+		require.NoError(t, err)
+		assert.Equal(t, "tenant-1", received.Get("X-Tenant-Token"))
+	})
+}
+
+func TestRESTClient_ExecuteWithRetry(t *testing.T) {
+	// Mirrors the activity /retry-test endpoint: fails twice, succeeds on the 3rd attempt
+	newRetryServer := func(status int) (*httptest.Server, *int) {
+		attempts := 0
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			attempts++
+			if attempts < 3 {
+				w.WriteHeader(status)
+				return
+			}
+			w.Header().Set("Content-Type", "application/json")
+			w.Write([]byte(`{"message":"success after retry"}`))
+		}))
+		return server, &attempts
+	}
+
+	policy := RetryPolicy{
+		MaxAttempts:          3,
+		InitialBackoff:       10 * time.Millisecond,
+		BackoffMultiplier:    2.0,
+		RetryableStatusCodes: []int{500, 503},
+	}
+
+	tests := []struct {
+		name             string
+		status           int
+		maxAttempts      int
+		expectedStatus   int
+		expectedAttempts int
+	}{
+		{
+			name:             "Succeeds after two failures",
+			status:           http.StatusInternalServerError,
+			maxAttempts:      3,
+			expectedStatus:   200,
+			expectedAttempts: 3,
+		},
+		{
+			name:             "Returns last response when attempts exhausted",
+			status:           http.StatusServiceUnavailable,
+			maxAttempts:      2,
+			expectedStatus:   503,
+			expectedAttempts: 2,
+		},
+		{
+			name:             "Non-retryable status returned immediately",
+			status:           http.StatusNotFound,
+			maxAttempts:      3,
+			expectedStatus:   404,
+			expectedAttempts: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server, attempts := newRetryServer(tt.status)
+			defer server.Close()
+
+			client, err := NewRESTClient(server.URL, AuthConfig{Type: NoAuth})
+			require.NoError(t, err)
+
+			p := policy
+			p.MaxAttempts = tt.maxAttempts
+			resp, err := client.ExecuteWithRetry(context.Background(), RESTRequest{
+				Method:   GET,
+				Endpoint: "/retry-test",
+			}, p)
+
+			require.NoError(t, err)
+			require.NotNil(t, resp)
+			assert.Equal(t, tt.expectedStatus, resp.StatusCode)
+			assert.Equal(t, tt.expectedAttempts, *attempts)
+		})
+	}
+
+	t.Run("Transport errors are retried then returned", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+		serverURL := server.URL
+		server.Close()
+
+		client, err := NewRESTClient(serverURL, AuthConfig{Type: NoAuth})
+		require.NoError(t, err)
+
+		resp, err := client.ExecuteWithRetry(context.Background(), RESTRequest{
+			Method:   GET,
+			Endpoint: "/retry-test",
+		}, policy)
+
+		assert.Error(t, err)
+		assert.Nil(t, resp)
+		assert.Contains(t, err.Error(), "failed after 3 attempts")
+	})
+
+	t.Run("Streamed bodies are not retried", func(t *testing.T) {
+		var received []string
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			body, _ := io.ReadAll(r.Body)
+			received = append(received, string(body))
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}))
+		defer server.Close()
+
+		client, err := NewRESTClient(server.URL, AuthConfig{Type: NoAuth})
+		require.NoError(t, err)
+
+		resp, err := client.ExecuteWithRetry(context.Background(), RESTRequest{
+			Method:   POST,
+			Endpoint: "/upload",
+			Body:     strings.NewReader("streamed payload"),
+		}, policy)
+
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusServiceUnavailable, resp.StatusCode)
+		assert.Equal(t, []string{"streamed payload"}, received, "the body must be sent exactly once")
+	})
+}
+
+func TestRESTClient_OnStatus(t *testing.T) {
+	server := createTestServer(t)
+	defer server.Close()
+
+	ctx := context.Background()
+
+	t.Run("Handler fires with the matching response", func(t *testing.T) {
+		client, err := NewRESTClient(server.URL, AuthConfig{Type: NoAuth})
+		require.NoError(t, err)
+
+		var handled *RESTResponse
+		client.OnStatus(http.StatusNotFound, func(resp *RESTResponse) error {
+			handled = resp
+			return nil
+		})
+
+		resp, err := client.GET(ctx, "/missing", nil)
+		require.NoError(t, err)
+
+		require.NotNil(t, handled)
+		assert.Same(t, resp, handled)
+		assert.Equal(t, 404, handled.StatusCode)
+		assert.Contains(t, handled.URL, "/missing")
+
+		// Other statuses don't trigger it
+		handled = nil
+		_, err = client.GET(ctx, "/users/1", nil)
+		require.NoError(t, err)
+		assert.Nil(t, handled)
+	})
+
+	t.Run("Handler error is returned by Execute", func(t *testing.T) {
+		errNotFound := errors.New("resource not found")
+		client, err := NewRESTClient(server.URL, AuthConfig{Type: NoAuth})
+		require.NoError(t, err)
+		client.OnStatus(http.StatusNotFound, func(resp *RESTResponse) error {
+			return errNotFound
+		})
+
+		resp, err := client.GET(ctx, "/missing", nil)
+
+		assert.ErrorIs(t, err, errNotFound)
+		assert.Nil(t, resp)
+	})
+}
+
+func TestRESTClient_ErrorStatusCodes(t *testing.T) {
+	server := createTestServer(t)
+	defer server.Close()
+
+	client, err := NewRESTClient(server.URL, AuthConfig{Type: NoAuth})
+	require.NoError(t, err)
+
+	tests := []struct {
+		name           string
+		endpoint       string
+		expectedStatus int
+		checkMethod    func(resp *RESTResponse) bool
+	}{
+		{
+			name:           "Client error (400)",
+			endpoint:       "/error/400",
+			expectedStatus: 400,
+			checkMethod:    (*RESTResponse).IsClientError,
+		},
+		{
+			name:           "Server error (500)",
+			endpoint:       "/error/500",
+			expectedStatus: 500,
+			checkMethod:    (*RESTResponse).IsServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			resp, err := client.GET(ctx, tt.endpoint, nil)
+
+			assert.NoError(t, err)
+			assert.NotNil(t, resp)
+			assert.Equal(t, tt.expectedStatus, resp.StatusCode)
+			assert.False(t, resp.IsSuccess())
+			assert.True(t, tt.checkMethod(resp))
+		})
+	}
+}
+
+func TestRESTClient_WithSuccessPredicate(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/failing" {
+			w.Header().Set("X-Status", "error")
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(`{"message":"ok"}`))
+	}))
+	defer server.Close()
+
+	client, err := NewRESTClient(server.URL, AuthConfig{Type: NoAuth})
+	require.NoError(t, err)
+	client.WithSuccessPredicate(func(resp *RESTResponse) bool {
+		return resp.IsSuccess() && http.Header(resp.Headers).Get("X-Status") != "error"
+	})
+
+	ctx := context.Background()
+
+	resp, err := client.GET(ctx, "/failing", nil)
+	require.NoError(t, err)
+	assert.Equal(t, 200, resp.StatusCode)
+	assert.False(t, resp.IsSuccess(), "error header should override the 2xx status")
+
+	resp, err = client.GET(ctx, "/ok", nil)
+	require.NoError(t, err)
+	assert.True(t, resp.IsSuccess())
+}
+
+func TestRESTClient_FailureStatusCodes(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusPartialContent)
+	}))
+	defer server.Close()
+
+	client, err := NewRESTClient(server.URL, AuthConfig{Type: NoAuth})
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	resp, err := client.GET(ctx, "/search", nil)
+	require.NoError(t, err)
+	assert.True(t, resp.IsSuccess())
+
+	client.FailureStatusCodes = []int{http.StatusPartialContent}
+	resp, err = client.GET(ctx, "/search", nil)
+	require.NoError(t, err)
+	assert.Equal(t, 206, resp.StatusCode)
+	assert.False(t, resp.IsSuccess())
+
+	// Failure codes win over a permissive success predicate
+	client.WithSuccessPredicate(func(*RESTResponse) bool { return true })
+	resp, err = client.GET(ctx, "/search", nil)
+	require.NoError(t, err)
+	assert.False(t, resp.IsSuccess())
+}
+
+func TestRESTResponse_UnmarshalData(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/users/1":
+			w.Write([]byte(`{"data":{"id":1,"name":"John Doe","email":"john@example.com"},"meta":{"version":"v1"}}`))
+		case "/users":
+			w.Write([]byte(`{"data":[{"id":1,"name":"John Doe"},{"id":2,"name":"Jane Smith"}],"meta":{"total":2}}`))
+		case "/result":
+			w.Write([]byte(`{"result":{"id":3,"name":"Custom Key"}}`))
+		default:
+			w.Write([]byte(`{"id":1,"name":"John Doe"}`))
+		}
+	}))
+	defer server.Close()
+
+	client, err := NewRESTClient(server.URL, AuthConfig{Type: NoAuth})
+	require.NoError(t, err)
+
+	ctx := context.Background()
+
+	t.Run("Object in envelope", func(t *testing.T) {
+		resp, err := client.GET(ctx, "/users/1", nil)
+		require.NoError(t, err)
+
+		var user TestUser
+		require.NoError(t, resp.UnmarshalData(&user))
+		assert.Equal(t, 1, user.ID)
+		assert.Equal(t, "John Doe", user.Name)
+	})
+
+	t.Run("Array in envelope", func(t *testing.T) {
+		resp, err := client.GET(ctx, "/users", nil)
+		require.NoError(t, err)
+
+		var users []TestUser
+		require.NoError(t, resp.UnmarshalData(&users))
+		require.Len(t, users, 2)
+		assert.Equal(t, "Jane Smith", users[1].Name)
+	})
+
+	t.Run("Missing envelope", func(t *testing.T) {
+		resp, err := client.GET(ctx, "/plain", nil)
+		require.NoError(t, err)
+
+		var user TestUser
+		err = resp.UnmarshalData(&user)
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "no 'data' field")
+	})
+
+	t.Run("Custom envelope key", func(t *testing.T) {
+		customClient, err := NewRESTClient(server.URL, AuthConfig{Type: NoAuth})
+		require.NoError(t, err)
+		customClient.EnvelopeKey = "result"
+
+		resp, err := customClient.GET(ctx, "/result", nil)
+		require.NoError(t, err)
+
+		var user TestUser
+		require.NoError(t, resp.UnmarshalData(&user))
+		assert.Equal(t, "Custom Key", user.Name)
+	})
+}
+
+func TestRESTResponse_UnmarshalJSON(t *testing.T) {
+	server := createTestServer(t)
+	defer server.Close()
+
+	client, err := NewRESTClient(server.URL, AuthConfig{Type: NoAuth})
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	resp, err := client.GET(ctx, "/users/1", nil)
+
+	require.NoError(t, err)
+	require.NotNil(t, resp)
+
+	var user TestUser
+	err = resp.UnmarshalJSON(&user)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 1, user.ID)
+	assert.Equal(t, "John Doe", user.Name)
+	assert.Equal(t, "john@example.com", user.Email)
+}
+
+func TestRESTResponse_UnmarshalJSON_EmptyBody(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/no-content" {
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	client, err := NewRESTClient(server.URL, AuthConfig{Type: NoAuth})
+	require.NoError(t, err)
+
+	ctx := context.Background()
+
+	t.Run("204 with empty body", func(t *testing.T) {
+		resp, err := client.DELETE(ctx, "/no-content")
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusNoContent, resp.StatusCode)
+
+		user := TestUser{ID: 7}
+		assert.NoError(t, resp.UnmarshalJSON(&user))
+		assert.Equal(t, TestUser{ID: 7}, user)
+	})
+
+	t.Run("200 with empty body", func(t *testing.T) {
+		resp, err := client.GET(ctx, "/empty", nil)
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+		user := TestUser{ID: 7}
+		assert.NoError(t, resp.UnmarshalJSON(&user))
+		assert.Equal(t, TestUser{ID: 7}, user)
+	})
+
+	t.Run("non-empty body still checks content type", func(t *testing.T) {
+		resp := &RESTResponse{StatusCode: http.StatusOK, ContentType: "text/plain", Body: []byte("ok")}
+
+		var user TestUser
+		assert.Error(t, resp.UnmarshalJSON(&user))
+	})
+}
+
+func TestRESTResponse_StrictUnmarshalJSON(t *testing.T) {
+	server := createTestServer(t)
+	defer server.Close()
+
+	client, err := NewRESTClient(server.URL, AuthConfig{Type: NoAuth})
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	resp, err := client.GET(ctx, "/users/1", nil)
+	require.NoError(t, err)
+	require.NotNil(t, resp)
+
+	t.Run("Matching struct succeeds", func(t *testing.T) {
+		var user TestUser
+		err := resp.StrictUnmarshalJSON(&user)
+
+		assert.NoError(t, err)
+		assert.Equal(t, 1, user.ID)
+		assert.Equal(t, "John Doe", user.Name)
+	})
+
+	t.Run("Unknown field fails", func(t *testing.T) {
+		var partial struct {
+			ID   int    `json:"id"`
+			Name string `json:"name"`
+		}
+		err := resp.StrictUnmarshalJSON(&partial)
+
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), `unknown field "email"`)
+
+		// The lenient variant still accepts it
+		assert.NoError(t, resp.UnmarshalJSON(&partial))
+	})
+}
+
+func TestRESTResponse_DecodeStream(t *testing.T) {
+	const count = 1000
+
+	var body strings.Builder
+	body.WriteString("[")
+	for i := 0; i < count; i++ {
+		if i > 0 {
+			body.WriteString(",")
+		}
+		fmt.Fprintf(&body, `{"id":%d,"name":"user-%d"}`, i, i)
+	}
+	body.WriteString("]")
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(body.String()))
+	}))
+	defer server.Close()
+
+	client, err := NewRESTClient(server.URL, AuthConfig{Type: NoAuth})
+	require.NoError(t, err)
+
+	resp, err := client.GET(context.Background(), "/users", nil)
+	require.NoError(t, err)
+
+	t.Run("Handler called per element", func(t *testing.T) {
+		calls := 0
+		err := resp.DecodeStream(func(element json.RawMessage) error {
+			var user TestUser
+			require.NoError(t, json.Unmarshal(element, &user))
+			assert.Equal(t, calls, user.ID, "elements are passed in order")
+			calls++
+			return nil
+		})
+
+		assert.NoError(t, err)
+		assert.Equal(t, count, calls)
+	})
+
+	t.Run("Handler error stops decoding", func(t *testing.T) {
+		errStop := errors.New("stop")
+		calls := 0
+		err := resp.DecodeStream(func(json.RawMessage) error {
+			calls++
+			if calls == 10 {
+				return errStop
+			}
+			return nil
+		})
+
+		assert.ErrorIs(t, err, errStop)
+		assert.Equal(t, 10, calls)
+	})
+
+	t.Run("Body is not an array", func(t *testing.T) {
+		resp := &RESTResponse{StatusCode: 200, ContentType: "application/json", Body: []byte(`{"id":1}`)}
+		err := resp.DecodeStream(func(json.RawMessage) error { return nil })
+
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "not a JSON array")
+	})
+
+	t.Run("Truncated array", func(t *testing.T) {
+		resp := &RESTResponse{StatusCode: 200, ContentType: "application/json", Body: []byte(`[{"id":1},{"id":2}`)}
+		calls := 0
+		err := resp.DecodeStream(func(json.RawMessage) error {
+			calls++
+			return nil
+		})
+
+		assert.Error(t, err)
+		assert.Equal(t, 2, calls)
+	})
+
+	t.Run("Empty body", func(t *testing.T) {
+		resp := &RESTResponse{StatusCode: http.StatusNoContent}
+		assert.NoError(t, resp.DecodeStream(func(json.RawMessage) error {
+			t.Fatal("handler must not be called")
+			return nil
+		}))
+	})
+}
+
+func TestRESTClient_StreamJSON(t *testing.T) {
+	firstSeen := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/users":
+			w.Header().Set("Content-Type", "application/json")
+			fmt.Fprint(w, `[{"id":0,"name":"user-0"},`)
+			w.(http.Flusher).Flush()
+
+			// The rest of the array is only sent once the first element was handled
+			select {
+			case <-firstSeen:
+			case <-time.After(2 * time.Second):
+				return
+			}
+			fmt.Fprint(w, `{"id":1,"name":"user-1"},{"id":2,"name":"user-2"}]`)
+		case "/empty":
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			fmt.Fprint(w, "no such resource")
+		}
+	}))
+	defer server.Close()
+
+	client, err := NewRESTClient(server.URL, AuthConfig{Type: NoAuth})
+	require.NoError(t, err)
+
+	ctx := context.Background()
+
+	t.Run("Elements are handled as they arrive", func(t *testing.T) {
+		var ids []int
+		err := client.StreamJSON(ctx, RESTRequest{Method: GET, Endpoint: "/users"}, func(element json.RawMessage) error {
+			var user TestUser
+			require.NoError(t, json.Unmarshal(element, &user))
+			if len(ids) == 0 {
+				close(firstSeen)
+			}
+			ids = append(ids, user.ID)
+			return nil
+		})
+
+		require.NoError(t, err)
+		assert.Equal(t, []int{0, 1, 2}, ids)
+	})
+
+	t.Run("No content", func(t *testing.T) {
+		err := client.StreamJSON(ctx, RESTRequest{Method: GET, Endpoint: "/empty"}, func(json.RawMessage) error {
+			t.Fatal("handler must not be called")
+			return nil
+		})
+		assert.NoError(t, err)
+	})
+
+	t.Run("Error status", func(t *testing.T) {
+		err := client.StreamJSON(ctx, RESTRequest{Method: GET, Endpoint: "/missing"}, func(json.RawMessage) error {
+			return nil
+		})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "HTTP 404: no such resource")
+	})
+}
+
+func TestTrackStream(t *testing.T) {
+	const delay = 10 * time.Millisecond
+
+	t.Run("DecodeStream", func(t *testing.T) {
+		resp := &RESTResponse{StatusCode: 200, ContentType: "application/json", Body: []byte(`[1,2,3,4,5]`)}
+
+		var stats StreamStats
+		err := resp.DecodeStream(TrackStream(&stats, func(json.RawMessage) error {
+			time.Sleep(delay)
+			return nil
+		}))
+
+		require.NoError(t, err)
+		assert.Equal(t, 5, stats.ElementsProcessed)
+		assert.GreaterOrEqual(t, stats.TotalCallbackDuration, 5*delay)
+	})
+
+	t.Run("StreamSSE", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "text/event-stream")
+			fmt.Fprint(w, "data: one\n\ndata: two\n\ndata: three\n\n")
+		}))
+		defer server.Close()
+
+		client, err := NewRESTClient(server.URL, AuthConfig{Type: NoAuth})
+		require.NoError(t, err)
+
+		var stats StreamStats
+		err = client.StreamSSE(context.Background(), "/events", nil, TrackStream(&stats, func(SSEEvent) error {
+			time.Sleep(delay)
+			return nil
+		}))
+
+		require.NoError(t, err)
+		assert.Equal(t, 3, stats.ElementsProcessed)
+		assert.GreaterOrEqual(t, stats.TotalCallbackDuration, 3*delay)
+	})
+
+	t.Run("Failed callback is counted", func(t *testing.T) {
+		resp := &RESTResponse{StatusCode: 200, ContentType: "application/json", Body: []byte(`[1,2,3]`)}
+		errStop := errors.New("stop")
+
+		var stats StreamStats
+		err := resp.DecodeStream(TrackStream(&stats, func(json.RawMessage) error {
+			return errStop
+		}))
+
+		assert.ErrorIs(t, err, errStop)
+		assert.Equal(t, 1, stats.ElementsProcessed)
+	})
+}
+
+func TestDecodeJSON(t *testing.T) {
+	server := createTestServer(t)
+	defer server.Close()
+
+	client, err := NewRESTClient(server.URL, AuthConfig{Type: NoAuth})
+	require.NoError(t, err)
+
+	ctx := context.Background()
+
+	t.Run("Decode into struct", func(t *testing.T) {
+		resp, err := client.GET(ctx, "/users/1", nil)
+		require.NoError(t, err)
+
+		user, err := DecodeJSON[TestUser](resp)
+
+		assert.NoError(t, err)
+		assert.Equal(t, 1, user.ID)
+		assert.Equal(t, "John Doe", user.Name)
+	})
+
+	t.Run("Decode into slice", func(t *testing.T) {
+		resp := &RESTResponse{
+			StatusCode:  200,
+			ContentType: "application/json",
+			Body:        []byte(`[{"id":1,"name":"John Doe"},{"id":2,"name":"Jane Smith"}]`),
+		}
+
+		users, err := DecodeJSON[[]TestUser](resp)
+
+		assert.NoError(t, err)
+		assert.Len(t, users, 2)
+		assert.Equal(t, "Jane Smith", users[1].Name)
+	})
+
+	t.Run("Content type is not JSON", func(t *testing.T) {
+		resp := &RESTResponse{
+			StatusCode:  200,
+			ContentType: "text/html",
+			Body:        []byte(`<html></html>`),
+		}
+
+		_, err := DecodeJSON[TestUser](resp)
+
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "HTTP 200")
+		assert.Contains(t, err.Error(), "not JSON")
+	})
+}
+
+func TestRESTResponse_JSON(t *testing.T) {
+	server := createTestServer(t)
+	defer server.Close()
+
+	client, err := NewRESTClient(server.URL, AuthConfig{Type: NoAuth})
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	resp, err := client.GET(ctx, "/users/1", nil)
+
+	require.NoError(t, err)
+	require.NotNil(t, resp)
+
+	jsonStr, err := resp.JSON()
+
+	assert.NoError(t, err)
+	assert.Contains(t, jsonStr, "John Doe")
+	assert.Contains(t, jsonStr, "john@example.com")
+}
+
+func TestRESTClient_ExpectXML(t *testing.T) {
+	type xmlUser struct {
+		XMLName xml.Name `xml:"user"`
+		ID      int      `xml:"id"`
+		Name    string   `xml:"name"`
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "application/xml", r.Header.Get("Accept"))
+
+		w.Header().Set("Content-Type", "application/xml")
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(`<user><id>1</id><name>John Doe</name></user>`))
+	}))
+	defer server.Close()
+
+	client, err := NewRESTClient(server.URL, AuthConfig{Type: NoAuth})
+	require.NoError(t, err)
+	client.ExpectXML()
+
+	ctx := context.Background()
+	resp, err := client.GET(ctx, "/users/1", nil)
+
+	require.NoError(t, err)
+	require.NotNil(t, resp)
+
+	var user xmlUser
+	err = resp.Unmarshal(&user)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 1, user.ID)
+	assert.Equal(t, "John Doe", user.Name)
+}
+
+func TestRESTClient_ExpectJSON(t *testing.T) {
+	server := createTestServer(t)
+	defer server.Close()
+
+	client, err := NewRESTClient(server.URL, AuthConfig{Type: NoAuth})
+	require.NoError(t, err)
+	client.ExpectXML().ExpectJSON()
+
+	assert.Equal(t, "application/json", client.defaultHeaders["Accept"])
+
+	ctx := context.Background()
+	resp, err := client.GET(ctx, "/users/1", nil)
+	require.NoError(t, err)
+
+	var user TestUser
+	err = resp.Unmarshal(&user)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "John Doe", user.Name)
+}
+
+func TestRESTResponse_Unmarshal(t *testing.T) {
+	type user struct {
+		XMLName xml.Name `json:"-" xml:"user"`
+		ID      int      `json:"id" xml:"id"`
+		Name    string   `json:"name" xml:"name"`
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/json":
+			w.Header().Set("Content-Type", "application/json; charset=utf-8")
+			w.Write([]byte(`{"id":1,"name":"John Doe"}`))
+		case "/xml":
+			w.Header().Set("Content-Type", "text/xml; charset=utf-8")
+			w.Write([]byte(`<user><id>1</id><name>John Doe</name></user>`))
+		case "/problem":
+			w.Header().Set("Content-Type", "application/problem+json")
+			w.Write([]byte(`{"id":1,"name":"John Doe"}`))
+		case "/html":
+			w.Header().Set("Content-Type", "text/html")
+			w.Write([]byte(`<html></html>`))
+		}
+	}))
+	defer server.Close()
+
+	client, err := NewRESTClient(server.URL, AuthConfig{Type: NoAuth})
+	require.NoError(t, err)
+
+	ctx := context.Background()
+
+	for _, endpoint := range []string{"/json", "/xml", "/problem"} {
+		t.Run("Decodes "+endpoint, func(t *testing.T) {
+			resp, err := client.GET(ctx, endpoint, nil)
+			require.NoError(t, err)
+
+			var decoded user
+			require.NoError(t, resp.Unmarshal(&decoded))
+			assert.Equal(t, 1, decoded.ID)
+			assert.Equal(t, "John Doe", decoded.Name)
+		})
+	}
+
+	t.Run("Unsupported content type", func(t *testing.T) {
+		resp, err := client.GET(ctx, "/html", nil)
+		require.NoError(t, err)
+
+		var decoded user
+		err = resp.Unmarshal(&decoded)
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "unsupported response content type: text/html")
+	})
+
+	t.Run("Missing content type uses the expected format", func(t *testing.T) {
+		resp := &RESTResponse{StatusCode: 200, Body: []byte(`<user><id>2</id></user>`), format: FormatXML}
+
+		var decoded user
+		require.NoError(t, resp.Unmarshal(&decoded))
+		assert.Equal(t, 2, decoded.ID)
+	})
+}
+
+func TestRESTClient_Accept(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "application/xml", r.Header.Get("Accept"))
+		assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+
+		body, _ := io.ReadAll(r.Body)
+		assert.JSONEq(t, `{"name":"John Doe"}`, string(body))
+
+		w.Header().Set("Content-Type", "application/xml")
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte(`<user><name>John Doe</name></user>`))
+	}))
+	defer server.Close()
+
+	client, err := NewRESTClient(server.URL, AuthConfig{Type: NoAuth})
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	resp, err := client.Execute(ctx, RESTRequest{
+		Method:   POST,
+		Endpoint: "/users",
+		Accept:   XMLContentType,
+		Body:     map[string]string{"name": "John Doe"},
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusCreated, resp.StatusCode)
+	assert.Equal(t, "application/json", client.defaultHeaders["Accept"])
+}
+
+// Run with -race to verify a shared client is safe for concurrent use
+func TestRESTClient_ConcurrentExecute(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "request-value", r.Header.Get("X-Request-Header"))
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(`{"id":1,"name":"John Doe"}`))
+	}))
+	defer server.Close()
+
+	client, err := NewRESTClient(server.URL, AuthConfig{Type: NoAuth})
+	require.NoError(t, err)
+
+	// Shared across goroutines; Execute must not mutate it
+	headers := map[string]string{"X-Request-Header": "request-value"}
+
+	stop := make(chan struct{})
+	mutatorDone := make(chan struct{})
+	go func() {
+		defer close(mutatorDone)
+		for i := 0; ; i++ {
+			select {
+			case <-stop:
+				return
+			default:
+			}
+			client.SetDefaultHeader("X-Tenant", fmt.Sprintf("tenant-%d", i))
+			if i%2 == 0 {
+				client.ExpectXML()
+			} else {
+				client.ExpectJSON()
+			}
+		}
+	}()
+
+	ctx := context.Background()
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 10; j++ {
+				resp, err := client.Execute(ctx, RESTRequest{
+					Method:   POST,
+					Endpoint: "/users",
+					Headers:  headers,
+					Body:     map[string]string{"name": "John Doe"},
+				})
+				if assert.NoError(t, err) {
+					assert.Equal(t, http.StatusOK, resp.StatusCode)
+				}
+			}
+		}()
+	}
+	wg.Wait()
+	close(stop)
+	<-mutatorDone
+
+	assert.Equal(t, map[string]string{"X-Request-Header": "request-value"}, headers)
+}
+
+func TestRESTClient_SetDefaultHeader(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "tenant-a", r.Header.Get("X-Tenant"))
+		assert.Equal(t, "override", r.Header.Get("X-Override"))
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	client, err := NewRESTClient(server.URL, AuthConfig{Type: NoAuth})
+	require.NoError(t, err)
+	client.SetDefaultHeader("X-Tenant", "tenant-a").SetDefaultHeader("X-Override", "default")
+
+	resp, err := client.Execute(context.Background(), RESTRequest{
+		Method:   GET,
+		Endpoint: "/ping",
+		Headers:  map[string]string{"X-Override": "override"},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	client.RemoveDefaultHeader("X-Tenant")
+	assert.NotContains(t, client.defaultHeaders, "X-Tenant")
+}
+
+func TestRESTClient_SniffContentType(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// A nil Content-Type stops net/http from detecting one itself
+		w.Header()["Content-Type"] = nil
+		w.WriteHeader(http.StatusOK)
+		if r.URL.Path == "/xml" {
+			w.Write([]byte(`<user><id>1</id><name>John Doe</name></user>`))
+			return
+		}
+		w.Write([]byte(`  {"id":1,"name":"John Doe","email":"john@example.com"}`))
+	}))
+	defer server.Close()
+
+	client, err := NewRESTClient(server.URL, AuthConfig{Type: NoAuth})
+	require.NoError(t, err)
+
+	ctx := context.Background()
+
+	t.Run("JSON body without content type", func(t *testing.T) {
+		resp, err := client.Execute(ctx, RESTRequest{
+			Method:           GET,
+			Endpoint:         "/json",
+			SniffContentType: true,
+		})
+		require.NoError(t, err)
+		assert.Equal(t, "application/json", resp.ContentType)
+
+		var user TestUser
+		assert.NoError(t, resp.Unmarshal(&user))
+		assert.Equal(t, "John Doe", user.Name)
+
+		var viaJSON TestUser
+		assert.NoError(t, resp.UnmarshalJSON(&viaJSON))
+		assert.Equal(t, 1, viaJSON.ID)
+	})
+
+	t.Run("XML body without content type", func(t *testing.T) {
+		type xmlUser struct {
+			XMLName xml.Name `xml:"user"`
+			Name    string   `xml:"name"`
+		}
+
+		resp, err := client.Execute(ctx, RESTRequest{
+			Method:           GET,
+			Endpoint:         "/xml",
+			SniffContentType: true,
+		})
+		require.NoError(t, err)
+		assert.Equal(t, "application/xml", resp.ContentType)
+
+		var user xmlUser
+		assert.NoError(t, resp.Unmarshal(&user))
+		assert.Equal(t, "John Doe", user.Name)
+	})
+
+	t.Run("Sniffing disabled", func(t *testing.T) {
+		resp, err := client.GET(ctx, "/json", nil)
+		require.NoError(t, err)
+		assert.Empty(t, resp.ContentType)
+
+		var user TestUser
+		err = resp.UnmarshalJSON(&user)
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "not JSON")
+	})
+}
+
+func TestRESTResponse_Text(t *testing.T) {
+	tests := []struct {
+		name        string
+		contentType string
+		body        []byte
+		expected    string
+		wantError   bool
+	}{
 		{
-			name:           "Client error (400)",
-			endpoint:       "/error/400",
-			expectedStatus: 400,
-			checkMethod:    (*RESTResponse).IsClientError,
+			name:        "Plain text response",
+			contentType: "text/plain; charset=utf-8",
+			body:        []byte("hello world"),
+			expected:    "hello world",
 		},
 		{
-			name:           "Server error (500)",
-			endpoint:       "/error/500",
-			expectedStatus: 500,
-			checkMethod:    (*RESTResponse).IsServerError,
+			name:        "JSON response",
+			contentType: "application/json",
+			body:        []byte(`{"message":"ok"}`),
+			expected:    `{"message":"ok"}`,
+		},
+		{
+			name:        "Binary response",
+			contentType: "application/octet-stream",
+			body:        []byte{0x00, 0x01, 0x02},
+			wantError:   true,
+		},
+		{
+			name:        "Image response",
+			contentType: "image/png",
+			body:        []byte{0x89, 0x50, 0x4e, 0x47},
+			wantError:   true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp := &RESTResponse{StatusCode: 200, ContentType: tt.contentType, Body: tt.body}
+
+			text, err := resp.Text()
+
+			if tt.wantError {
+				assert.Error(t, err)
+				assert.Contains(t, err.Error(), "not textual")
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.expected, text)
+			}
+		})
+	}
+}
+
+func TestRESTResponse_Cookies(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.SetCookie(w, &http.Cookie{Name: "session", Value: "abc123", Path: "/", HttpOnly: true})
+		http.SetCookie(w, &http.Cookie{Name: "theme", Value: "dark", Path: "/app"})
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(`{"message":"logged in"}`))
+	}))
+	defer server.Close()
+
+	client, err := NewRESTClient(server.URL, AuthConfig{Type: NoAuth})
+	require.NoError(t, err)
+
+	resp, err := client.POST(context.Background(), "/login", map[string]string{"user": "john"})
+	require.NoError(t, err)
+
+	cookies := resp.Cookies()
+	require.Len(t, cookies, 2)
+	assert.Equal(t, "session", cookies[0].Name)
+	assert.Equal(t, "theme", cookies[1].Name)
+
+	session, ok := resp.Cookie("session")
+	require.True(t, ok)
+	assert.Equal(t, "abc123", session.Value)
+	assert.Equal(t, "/", session.Path)
+	assert.True(t, session.HttpOnly)
+
+	theme, ok := resp.Cookie("theme")
+	require.True(t, ok)
+	assert.Equal(t, "dark", theme.Value)
+	assert.Equal(t, "/app", theme.Path)
+	assert.False(t, theme.HttpOnly)
+
+	_, ok = resp.Cookie("missing")
+	assert.False(t, ok)
+}
+
+func TestRESTResponse_GetField(t *testing.T) {
+	resp := &RESTResponse{
+		StatusCode:  200,
+		ContentType: "application/json",
+		Body: []byte(`{
+			"id": 1,
+			"name": "John Doe",
+			"address": {"city": "Springfield", "geo": {"lat": "40.1"}},
+			"roles": [{"name": "admin"}, {"name": "editor"}]
+		}`),
+	}
+
+	tests := []struct {
+		name      string
+		path      string
+		expected  interface{}
+		wantError bool
+	}{
+		{name: "Top-level field", path: "name", expected: "John Doe"},
+		{name: "Numeric field", path: "id", expected: float64(1)},
+		{name: "Nested field", path: "address.city", expected: "Springfield"},
+		{name: "Deeply nested field", path: "address.geo.lat", expected: "40.1"},
+		{name: "Array index", path: "roles.1.name", expected: "editor"},
+		{name: "Missing field", path: "address.zip", wantError: true},
+		{name: "Index out of range", path: "roles.5.name", wantError: true},
+		{name: "Path through scalar", path: "name.first", wantError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			value, err := resp.GetField(tt.path)
+
+			if tt.wantError {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.expected, value)
+			}
+		})
+	}
+}
+
+// recordingT captures assertion failures without failing the enclosing test
+type recordingT struct {
+	failures []string
+}
+
+func (r *recordingT) Helper() {}
+
+func (r *recordingT) Errorf(format string, args ...interface{}) {
+	r.failures = append(r.failures, fmt.Sprintf(format, args...))
+}
+
+func TestAssert(t *testing.T) {
+	server := createTestServer(t)
+	defer server.Close()
+
+	client, err := NewRESTClient(server.URL, AuthConfig{Type: NoAuth})
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	resp, err := client.GET(ctx, "/users/1", nil)
+	require.NoError(t, err)
+
+	t.Run("Passing assertions", func(t *testing.T) {
+		rec := &recordingT{}
+
+		Assert(rec, resp).
+			Status(200).
+			HeaderEquals("Content-Type", "application/json").
+			JSONField("id", 1).
+			JSONField("name", "John Doe")
+
+		assert.Empty(t, rec.failures)
+	})
+
+	t.Run("Wrong status", func(t *testing.T) {
+		rec := &recordingT{}
+
+		Assert(rec, resp).Status(404)
+
+		require.Len(t, rec.failures, 1)
+		assert.Contains(t, rec.failures[0], "expected status code 404, got 200")
+	})
+
+	t.Run("Wrong header", func(t *testing.T) {
+		rec := &recordingT{}
+
+		Assert(rec, resp).HeaderEquals("Content-Type", "application/xml")
+
+		require.Len(t, rec.failures, 1)
+		assert.Contains(t, rec.failures[0], "Content-Type")
+	})
+
+	t.Run("Wrong and missing JSON fields", func(t *testing.T) {
+		rec := &recordingT{}
+
+		Assert(rec, resp).
+			JSONField("id", 2).
+			JSONField("address.city", "Springfield")
+
+		require.Len(t, rec.failures, 2)
+		assert.Contains(t, rec.failures[0], "expected JSON field 'id' to be 2, got 1")
+		assert.Contains(t, rec.failures[1], "address.city")
+	})
+
+	t.Run("Chain continues after a failure", func(t *testing.T) {
+		rec := &recordingT{}
+
+		Assert(rec, resp).Status(500).JSONField("id", 1).JSONField("email", "wrong@example.com")
+
+		assert.Len(t, rec.failures, 2)
+	})
+
+	t.Run("Nil response", func(t *testing.T) {
+		rec := &recordingT{}
+
+		Assert(rec, nil).Status(200).JSONField("id", 1)
+
+		require.Len(t, rec.failures, 1)
+		assert.Contains(t, rec.failures[0], "got nil")
+	})
+}
+
+func TestRESTClient_CustomHeaders(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Check custom headers
+		assert.Equal(t, "application/xml", r.Header.Get("Content-Type"))
+		assert.Equal(t, "custom-value", r.Header.Get("X-Custom-Header"))
+		assert.Equal(t, "MyApp/1.0", r.Header.Get("User-Agent"))
+
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(`{"message":"ok"}`))
+	}))
+	defer server.Close()
+
+	client, err := NewRESTClient(server.URL, AuthConfig{Type: NoAuth})
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	req := RESTRequest{
+		Method:   POST,
+		Endpoint: "/test",
+		Headers: map[string]string{
+			"Content-Type":     "application/xml",
+			"X-Custom-Header":  "custom-value",
+			"User-Agent":       "MyApp/1.0",
+		},
+		Body: `<test>data</test>`,
+	}
+
+	resp, err := client.Execute(ctx, req)
+
+	assert.NoError(t, err)
+	assert.NotNil(t, resp)
+	assert.Equal(t, 200, resp.StatusCode)
+}
+
+func TestRESTClient_FormData(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "application/x-www-form-urlencoded", r.Header.Get("Content-Type"))
+
+		body, _ := io.ReadAll(r.Body)
+		bodyStr := string(body)
+		assert.Contains(t, bodyStr, "name=John")
+		assert.Contains(t, bodyStr, "email=john%40example.com")
+
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(`{"message":"form received"}`))
+	}))
+	defer server.Close()
+
+	client, err := NewRESTClient(server.URL, AuthConfig{Type: NoAuth})
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	req := RESTRequest{
+		Method:   POST,
+		Endpoint: "/form",
+		Headers: map[string]string{
+			"Content-Type": "application/x-www-form-urlencoded",
+		},
+		Body: map[string]interface{}{
+			"name":  "John",
+			"email": "john@example.com",
+		},
+	}
+
+	resp, err := client.Execute(ctx, req)
+
+	assert.NoError(t, err)
+	assert.NotNil(t, resp)
+	assert.Equal(t, 200, resp.StatusCode)
+}
+
+func TestRESTClient_CanonicalJSON(t *testing.T) {
+	type payment struct {
+		Currency string                 `json:"currency"`
+		Amount   json.Number            `json:"amount"`
+		Meta     map[string]interface{} `json:"meta"`
+	}
+
+	client, err := NewRESTClient("https://api.example.com", AuthConfig{Type: NoAuth})
+	require.NoError(t, err)
+	client.CanonicalJSON = true
+
+	expected := `{"amount":12345678901234567890,"currency":"USD","meta":{"a":[3,1],"b":true,"z":"last"}}`
+
+	first, err := client.marshalRequestBody(map[string]interface{}{
+		"meta":     map[string]interface{}{"z": "last", "a": []int{3, 1}, "b": true},
+		"currency": "USD",
+		"amount":   json.Number("12345678901234567890"),
+	}, nil)
+	require.NoError(t, err)
+
+	second, err := client.marshalRequestBody(payment{
+		Currency: "USD",
+		Amount:   json.Number("12345678901234567890"),
+		Meta:     map[string]interface{}{"b": true, "z": "last", "a": []int{3, 1}},
+	}, nil)
+	require.NoError(t, err)
+
+	assert.Equal(t, expected, string(first))
+	assert.Equal(t, first, second, "map and struct bodies should serialize byte-identically")
+
+	t.Run("Sent body", func(t *testing.T) {
+		var received []byte
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			received, _ = io.ReadAll(r.Body)
+			w.WriteHeader(http.StatusOK)
+		}))
+		defer server.Close()
+
+		client, err := NewRESTClient(server.URL, AuthConfig{Type: NoAuth})
+		require.NoError(t, err)
+		client.CanonicalJSON = true
+
+		_, err = client.POST(context.Background(), "/payments", payment{Currency: "USD", Amount: "10"})
+		require.NoError(t, err)
+		assert.Equal(t, `{"amount":10,"currency":"USD","meta":null}`, string(received))
+	})
+}
+
+func TestMarshalFormData(t *testing.T) {
+	client, err := NewRESTClient("https://api.example.com", AuthConfig{Type: NoAuth})
+	require.NoError(t, err)
+
+	tests := []struct {
+		name     string
+		body     interface{}
+		expected url.Values
+	}{
+		{
+			name: "Scalars",
+			body: map[string]interface{}{
+				"name":   "John",
+				"age":    30,
+				"id":     12345678901,
+				"active": true,
+			},
+			expected: url.Values{
+				"name":   {"John"},
+				"age":    {"30"},
+				"id":     {"12345678901"},
+				"active": {"true"},
+			},
+		},
+		{
+			name: "Slice expands to repeated keys",
+			body: map[string]interface{}{
+				"tags": []string{"a", "b"},
+			},
+			expected: url.Values{
+				"tags": {"a", "b"},
+			},
+		},
+		{
+			name: "Nested map is JSON-encoded",
+			body: map[string]interface{}{
+				"address": map[string]interface{}{"city": "Berlin", "zip": "10115"},
+			},
+			expected: url.Values{
+				"address": {`{"city":"Berlin","zip":"10115"}`},
+			},
+		},
+		{
+			name: "Slice of objects JSON-encodes each item",
+			body: map[string]interface{}{
+				"items": []map[string]int{{"qty": 1}, {"qty": 2}},
+			},
+			expected: url.Values{
+				"items": {`{"qty":1}`, `{"qty":2}`},
+			},
 		},
 	}
 
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			ctx := context.Background()
-			resp, err := client.GET(ctx, tt.endpoint, nil)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			encoded, err := client.marshalFormData(tt.body)
+			require.NoError(t, err)
+
+			values, err := url.ParseQuery(string(encoded))
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, values)
+		})
+	}
+}
+
+func TestRESTClient_Protobuf(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "application/x-protobuf", r.Header.Get("Content-Type"))
+
+		body, _ := io.ReadAll(r.Body)
+		var in wrapperspb.StringValue
+		require.NoError(t, proto.Unmarshal(body, &in))
+
+		out, err := proto.Marshal(wrapperspb.String("echo: " + in.GetValue()))
+		require.NoError(t, err)
+
+		w.Header().Set("Content-Type", "application/x-protobuf")
+		w.WriteHeader(http.StatusOK)
+		w.Write(out)
+	}))
+	defer server.Close()
+
+	client, err := NewRESTClient(server.URL, AuthConfig{Type: NoAuth})
+	require.NoError(t, err)
+
+	ctx := context.Background()
+
+	t.Run("Round trip", func(t *testing.T) {
+		req := RESTRequest{
+			Method:   POST,
+			Endpoint: "/echo",
+			Headers: map[string]string{
+				"Content-Type": "application/x-protobuf",
+			},
+			Body: wrapperspb.String("hello"),
+		}
+
+		resp, err := client.Execute(ctx, req)
+		require.NoError(t, err)
+		assert.Equal(t, 200, resp.StatusCode)
+
+		var result wrapperspb.StringValue
+		require.NoError(t, resp.UnmarshalProto(&result))
+		assert.Equal(t, "echo: hello", result.GetValue())
+	})
+
+	t.Run("Non-proto body", func(t *testing.T) {
+		req := RESTRequest{
+			Method:   POST,
+			Endpoint: "/echo",
+			Headers: map[string]string{
+				"Content-Type": "application/x-protobuf",
+			},
+			Body: map[string]string{"value": "hello"},
+		}
 
-			assert.NoError(t, err)
-			assert.NotNil(t, resp)
-			assert.Equal(t, tt.expectedStatus, resp.StatusCode)
-			assert.False(t, resp.IsSuccess())
-			assert.True(t, tt.checkMethod(resp))
-		})
-	}
+		_, err := client.Execute(ctx, req)
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "proto.Message")
+	})
+
+	t.Run("Non-protobuf response", func(t *testing.T) {
+		resp := &RESTResponse{ContentType: "application/json", Body: []byte(`{}`)}
+
+		var result wrapperspb.StringValue
+		err := resp.UnmarshalProto(&result)
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "not protobuf")
+	})
 }
 
-func TestRESTResponse_UnmarshalJSON(t *testing.T) {
-	server := createTestServer(t)
+func TestRESTClient_ValidateRequestBody(t *testing.T) {
+	type createUserRequest struct {
+		Name  string `json:"name" validate:"required"`
+		Email string `json:"email" validate:"required,email"`
+	}
+
+	requestSent := false
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requestSent = true
+		w.WriteHeader(http.StatusCreated)
+	}))
 	defer server.Close()
 
 	client, err := NewRESTClient(server.URL, AuthConfig{Type: NoAuth})
 	require.NoError(t, err)
 
 	ctx := context.Background()
-	resp, err := client.GET(ctx, "/users/1", nil)
 
-	require.NoError(t, err)
-	require.NotNil(t, resp)
+	t.Run("Missing required field", func(t *testing.T) {
+		req := RESTRequest{
+			Method:              POST,
+			Endpoint:            "/users",
+			Body:                createUserRequest{Name: "Incomplete User"},
+			ValidateRequestBody: true,
+		}
 
-	var user TestUser
-	err = resp.UnmarshalJSON(&user)
+		resp, err := client.Execute(ctx, req)
 
-	assert.NoError(t, err)
-	assert.Equal(t, 1, user.ID)
-	assert.Equal(t, "John Doe", user.Name)
-	assert.Equal(t, "john@example.com", user.Email)
+		assert.Error(t, err)
+		assert.Nil(t, resp)
+		assert.Contains(t, err.Error(), "request body validation failed")
+		assert.Contains(t, err.Error(), "Email")
+		assert.False(t, requestSent, "Request should not be sent when validation fails")
+	})
+
+	t.Run("Valid body", func(t *testing.T) {
+		req := RESTRequest{
+			Method:              POST,
+			Endpoint:            "/users",
+			Body:                &createUserRequest{Name: "Alice Johnson", Email: "alice@example.com"},
+			ValidateRequestBody: true,
+		}
+
+		resp, err := client.Execute(ctx, req)
+
+		assert.NoError(t, err)
+		assert.Equal(t, 201, resp.StatusCode)
+		assert.True(t, requestSent)
+	})
 }
 
-func TestRESTResponse_JSON(t *testing.T) {
-	server := createTestServer(t)
+func TestRESTClient_CompressBody(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var reader io.Reader = r.Body
+		if r.Header.Get("Content-Encoding") == "gzip" {
+			gz, err := gzip.NewReader(r.Body)
+			if err != nil {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			defer gz.Close()
+			reader = gz
+		}
+
+		body, _ := io.ReadAll(reader)
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("X-Received-Encoding", r.Header.Get("Content-Encoding"))
+		w.WriteHeader(http.StatusOK)
+		w.Write(body)
+	}))
 	defer server.Close()
 
 	client, err := NewRESTClient(server.URL, AuthConfig{Type: NoAuth})
 	require.NoError(t, err)
 
 	ctx := context.Background()
-	resp, err := client.GET(ctx, "/users/1", nil)
 
-	require.NoError(t, err)
-	require.NotNil(t, resp)
+	t.Run("Large body is compressed", func(t *testing.T) {
+		users := make([]TestUser, 100)
+		for i := range users {
+			users[i] = TestUser{ID: i, Name: fmt.Sprintf("User %d", i), Email: fmt.Sprintf("user%d@example.com", i)}
+		}
 
-	jsonStr, err := resp.JSON()
+		resp, err := client.Execute(ctx, RESTRequest{
+			Method:       POST,
+			Endpoint:     "/echo",
+			Body:         users,
+			CompressBody: true,
+		})
 
-	assert.NoError(t, err)
-	assert.Contains(t, jsonStr, "John Doe")
-	assert.Contains(t, jsonStr, "john@example.com")
+		require.NoError(t, err)
+		assert.Equal(t, "gzip", resp.Headers["X-Received-Encoding"][0])
+
+		var echoed []TestUser
+		err = json.Unmarshal(resp.Body, &echoed)
+		assert.NoError(t, err)
+		assert.Equal(t, users, echoed)
+	})
+
+	t.Run("Small body is sent uncompressed", func(t *testing.T) {
+		user := TestUser{ID: 1, Name: "John Doe", Email: "john@example.com"}
+
+		resp, err := client.Execute(ctx, RESTRequest{
+			Method:       POST,
+			Endpoint:     "/echo",
+			Body:         user,
+			CompressBody: true,
+		})
+
+		require.NoError(t, err)
+		assert.Equal(t, "", resp.Headers["X-Received-Encoding"][0])
+
+		var echoed TestUser
+		err = json.Unmarshal(resp.Body, &echoed)
+		assert.NoError(t, err)
+		assert.Equal(t, user, echoed)
+	})
 }
 
-func TestRESTClient_CustomHeaders(t *testing.T) {
+func TestRESTClient_RequestGzipAcceptEncoding(t *testing.T) {
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		// Check custom headers
-		assert.Equal(t, "application/xml", r.Header.Get("Content-Type"))
-		assert.Equal(t, "custom-value", r.Header.Get("X-Custom-Header"))
-		assert.Equal(t, "MyApp/1.0", r.Header.Get("User-Agent"))
+		var reader io.Reader = r.Body
+		if r.Header.Get("Content-Encoding") == "gzip" {
+			gz, err := gzip.NewReader(r.Body)
+			if err != nil {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			defer gz.Close()
+			reader = gz
+		}
+		body, _ := io.ReadAll(reader)
 
-		w.WriteHeader(http.StatusOK)
-		w.Write([]byte(`{"message":"ok"}`))
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("X-Received-Accept-Encoding", r.Header.Get("Accept-Encoding"))
+		if !strings.Contains(r.Header.Get("Accept-Encoding"), "gzip") {
+			w.Write(body)
+			return
+		}
+		w.Header().Set("Content-Encoding", "gzip")
+		gz := gzip.NewWriter(w)
+		gz.Write(body)
+		gz.Close()
+	}))
+	defer server.Close()
+
+	users := make([]TestUser, 100)
+	for i := range users {
+		users[i] = TestUser{ID: i, Name: fmt.Sprintf("User %d", i), Email: fmt.Sprintf("user%d@example.com", i)}
+	}
+
+	newClient := func(t *testing.T, acceptGzip bool) *RESTClient {
+		client, err := NewRESTClient(server.URL, AuthConfig{Type: NoAuth})
+		require.NoError(t, err)
+		// A custom transport that never negotiates compression on its own
+		client.HTTPClient().Transport = &http.Transport{DisableCompression: true}
+		client.RequestGzipAcceptEncoding = acceptGzip
+		return client
+	}
+
+	t.Run("Gzip in both directions with a custom transport", func(t *testing.T) {
+		client := newClient(t, true)
+
+		resp, err := client.Execute(context.Background(), RESTRequest{
+			Method:       POST,
+			Endpoint:     "/echo",
+			Body:         users,
+			CompressBody: true,
+		})
+		require.NoError(t, err)
+
+		assert.Equal(t, "gzip", http.Header(resp.Headers).Get("X-Received-Accept-Encoding"))
+		assert.Empty(t, http.Header(resp.Headers).Get("Content-Encoding"), "decoded responses drop Content-Encoding")
+		assert.Equal(t, int64(-1), resp.ContentLength)
+
+		var echoed []TestUser
+		require.NoError(t, resp.UnmarshalJSON(&echoed))
+		assert.Equal(t, users, echoed)
+	})
+
+	t.Run("Request header overrides the option", func(t *testing.T) {
+		client := newClient(t, true)
+
+		resp, err := client.Execute(context.Background(), RESTRequest{
+			Method:   POST,
+			Endpoint: "/echo",
+			Headers:  map[string]string{"Accept-Encoding": "identity"},
+			Body:     users[0],
+		})
+		require.NoError(t, err)
+
+		assert.Equal(t, "identity", http.Header(resp.Headers).Get("X-Received-Accept-Encoding"))
+		var echoed TestUser
+		require.NoError(t, resp.UnmarshalJSON(&echoed))
+		assert.Equal(t, users[0], echoed)
+	})
+
+	t.Run("Disabled by default", func(t *testing.T) {
+		client := newClient(t, false)
+
+		resp, err := client.POST(context.Background(), "/echo", users[0])
+		require.NoError(t, err)
+		assert.Empty(t, http.Header(resp.Headers).Get("X-Received-Accept-Encoding"))
+	})
+}
+
+func TestRESTClient_CaptureWire(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("X-Audit", "yes")
+		w.WriteHeader(http.StatusCreated)
+		w.Write(body)
+	}))
+	defer server.Close()
+
+	user := TestUser{ID: 1, Name: "John Doe", Email: "john@example.com"}
+
+	t.Run("Enabled", func(t *testing.T) {
+		client, err := NewRESTClient(server.URL, AuthConfig{Type: BearerAuth, Token: "secret-token"})
+		require.NoError(t, err)
+		client.CaptureWire = true
+
+		resp, err := client.POST(context.Background(), "/users", user)
+		require.NoError(t, err)
+
+		// Normal processing still sees the full body
+		var echoed TestUser
+		require.NoError(t, resp.UnmarshalJSON(&echoed))
+		assert.Equal(t, user, echoed)
+
+		require.NotEmpty(t, resp.RawRequest)
+		rawReq, err := http.ReadRequest(bufio.NewReader(bytes.NewReader(resp.RawRequest)))
+		require.NoError(t, err)
+		assert.Equal(t, "POST", rawReq.Method)
+		assert.Equal(t, "/users", rawReq.URL.Path)
+		assert.Equal(t, "Bearer secret-token", rawReq.Header.Get("Authorization"))
+		reqBody, err := io.ReadAll(rawReq.Body)
+		require.NoError(t, err)
+		assert.JSONEq(t, `{"id":1,"name":"John Doe","email":"john@example.com"}`, string(reqBody))
+
+		require.NotEmpty(t, resp.RawResponse)
+		rawResp, err := http.ReadResponse(bufio.NewReader(bytes.NewReader(resp.RawResponse)), rawReq)
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusCreated, rawResp.StatusCode)
+		assert.Equal(t, "yes", rawResp.Header.Get("X-Audit"))
+		respBody, err := io.ReadAll(rawResp.Body)
+		require.NoError(t, err)
+		assert.Equal(t, resp.Body, respBody)
+	})
+
+	t.Run("Disabled by default", func(t *testing.T) {
+		client, err := NewRESTClient(server.URL, AuthConfig{Type: NoAuth})
+		require.NoError(t, err)
+
+		resp, err := client.POST(context.Background(), "/users", user)
+		require.NoError(t, err)
+		assert.Nil(t, resp.RawRequest)
+		assert.Nil(t, resp.RawResponse)
+	})
+}
+
+// decodeCountingUser counts how many times it is unmarshaled
+type decodeCountingUser TestUser
+
+var userDecodes int
+
+func (u *decodeCountingUser) UnmarshalJSON(data []byte) error {
+	userDecodes++
+	return json.Unmarshal(data, (*TestUser)(u))
+}
+
+func TestTypedCache(t *testing.T) {
+	serverHits := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		serverHits++
+		if r.Header.Get("If-None-Match") == `"v1"` {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("ETag", `"v1"`)
+		w.Write([]byte(`{"id":1,"name":"John Doe","email":"john@example.com"}`))
 	}))
 	defer server.Close()
 
 	client, err := NewRESTClient(server.URL, AuthConfig{Type: NoAuth})
 	require.NoError(t, err)
 
+	userDecodes = 0
+	cache := NewTypedCache[decodeCountingUser](client, 50*time.Millisecond)
 	ctx := context.Background()
-	req := RESTRequest{
-		Method:   POST,
-		Endpoint: "/test",
-		Headers: map[string]string{
-			"Content-Type":     "application/xml",
-			"X-Custom-Header":  "custom-value",
-			"User-Agent":       "MyApp/1.0",
-		},
-		Body: `<test>data</test>`,
-	}
 
-	resp, err := client.Execute(ctx, req)
+	user, err := cache.Get(ctx, "/users/1", nil)
+	require.NoError(t, err)
+	assert.Equal(t, "John Doe", user.Name)
+	assert.Equal(t, 1, serverHits)
+	assert.Equal(t, 1, userDecodes)
 
-	assert.NoError(t, err)
-	assert.NotNil(t, resp)
-	assert.Equal(t, 200, resp.StatusCode)
+	// Within TTL: no request, no decode
+	user, err = cache.Get(ctx, "/users/1", nil)
+	require.NoError(t, err)
+	assert.Equal(t, "John Doe", user.Name)
+	assert.Equal(t, 1, serverHits, "second GET within TTL should not hit the server")
+	assert.Equal(t, 1, userDecodes, "second GET within TTL should not decode again")
+
+	// After TTL: revalidated via ETag, 304 reuses the decoded value
+	time.Sleep(80 * time.Millisecond)
+	user, err = cache.Get(ctx, "/users/1", nil)
+	require.NoError(t, err)
+	assert.Equal(t, "John Doe", user.Name)
+	assert.Equal(t, 2, serverHits)
+	assert.Equal(t, 1, userDecodes, "304 response should reuse the cached value")
+
+	// Invalidated: full fetch and decode
+	cache.Invalidate("/users/1", nil)
+	_, err = cache.Get(ctx, "/users/1", nil)
+	require.NoError(t, err)
+	assert.Equal(t, 3, serverHits)
+	assert.Equal(t, 2, userDecodes)
 }
 
-func TestRESTClient_FormData(t *testing.T) {
+func TestRequestBuilder(t *testing.T) {
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		assert.Equal(t, "application/x-www-form-urlencoded", r.Header.Get("Content-Type"))
+		assert.Equal(t, "/users", r.URL.Path)
+		assert.Equal(t, "admin", r.URL.Query().Get("role"))
+		assert.Equal(t, "custom-value", r.Header.Get("X-Custom-Header"))
+		assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
 
 		body, _ := io.ReadAll(r.Body)
-		bodyStr := string(body)
-		assert.Contains(t, bodyStr, "name=John")
-		assert.Contains(t, bodyStr, "email=john%40example.com")
+		assert.JSONEq(t, `{"name":"John Doe"}`, string(body))
 
-		w.WriteHeader(http.StatusOK)
-		w.Write([]byte(`{"message":"form received"}`))
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte(`{"id":1,"name":"John Doe"}`))
 	}))
 	defer server.Close()
 
 	client, err := NewRESTClient(server.URL, AuthConfig{Type: NoAuth})
 	require.NoError(t, err)
 
-	ctx := context.Background()
-	req := RESTRequest{
-		Method:   POST,
-		Endpoint: "/form",
+	body := map[string]string{"name": "John Doe"}
+	builder := client.NewRequest(POST, "/users").
+		Query("role", "admin").
+		Header("X-Custom-Header", "custom-value").
+		JSONBody(body).
+		Timeout(5 * time.Second)
+
+	expected := RESTRequest{
+		Method:      POST,
+		Endpoint:    "/users",
+		QueryParams: map[string]string{"role": "admin"},
 		Headers: map[string]string{
-			"Content-Type": "application/x-www-form-urlencoded",
-		},
-		Body: map[string]interface{}{
-			"name":  "John",
-			"email": "john@example.com",
+			"X-Custom-Header": "custom-value",
+			"Content-Type":    "application/json",
 		},
+		Body:    body,
+		Timeout: 5 * time.Second,
 	}
+	assert.Equal(t, expected, builder.Build())
 
-	resp, err := client.Execute(ctx, req)
+	ctx := context.Background()
+	built, err := client.BuildRequest(ctx, builder.Build())
+	require.NoError(t, err)
+	direct, err := client.BuildRequest(ctx, expected)
+	require.NoError(t, err)
+	assert.Equal(t, direct.URL.String(), built.URL.String())
+	assert.Equal(t, direct.Header, built.Header)
 
-	assert.NoError(t, err)
-	assert.NotNil(t, resp)
-	assert.Equal(t, 200, resp.StatusCode)
+	resp, err := builder.Do(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusCreated, resp.StatusCode)
 }
 
 // Benchmark tests
@@ -733,4 +4200,234 @@ func BenchmarkRESTClient_POST(b *testing.B) {
 			b.Fatal(err)
 		}
 	}
-}
\ No newline at end of file
+}
+
+func TestRESTClient_StreamSSE(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/events", r.URL.Path)
+		assert.Equal(t, "orders", r.URL.Query().Get("topic"))
+		assert.Equal(t, "text/event-stream", r.Header.Get("Accept"))
+
+		w.Header().Set("Content-Type", "text/event-stream")
+		flusher := w.(http.Flusher)
+
+		events := []string{
+			": keep-alive\n\n",
+			"id: 1\nevent: created\ndata: {\"order\":1}\n\n",
+			"id: 2\nevent: updated\ndata: first line\ndata: second line\n\n",
+			"data:no space\n\n",
+		}
+		for _, event := range events {
+			fmt.Fprint(w, event)
+			flusher.Flush()
+		}
+	}))
+	defer server.Close()
+
+	client, err := NewRESTClient(server.URL, AuthConfig{Type: NoAuth})
+	require.NoError(t, err)
+
+	var received []SSEEvent
+	err = client.StreamSSE(context.Background(), "/events", map[string]string{"topic": "orders"}, func(event SSEEvent) error {
+		received = append(received, event)
+		return nil
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, []SSEEvent{
+		{ID: "1", Event: "created", Data: `{"order":1}`},
+		{ID: "2", Event: "updated", Data: "first line\nsecond line"},
+		{ID: "2", Data: "no space"},
+	}, received)
+
+	t.Run("handler error stops the stream", func(t *testing.T) {
+		stop := errors.New("stop")
+		calls := 0
+		err := client.StreamSSE(context.Background(), "/events", map[string]string{"topic": "orders"}, func(event SSEEvent) error {
+			calls++
+			return stop
+		})
+		assert.ErrorIs(t, err, stop)
+		assert.Equal(t, 1, calls)
+	})
+
+	t.Run("context cancellation", func(t *testing.T) {
+		blocking := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "text/event-stream")
+			fmt.Fprint(w, "data: hello\n\n")
+			w.(http.Flusher).Flush()
+			<-r.Context().Done()
+		}))
+		defer blocking.Close()
+
+		blockingClient, err := NewRESTClient(blocking.URL, AuthConfig{Type: NoAuth})
+		require.NoError(t, err)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		err = blockingClient.StreamSSE(ctx, "/", nil, func(event SSEEvent) error {
+			assert.Equal(t, "hello", event.Data)
+			cancel()
+			return nil
+		})
+		assert.ErrorIs(t, err, context.Canceled)
+	})
+
+	t.Run("logs the stream", func(t *testing.T) {
+		logged, err := NewRESTClient(server.URL, AuthConfig{Type: NoAuth})
+		require.NoError(t, err)
+		handler := &capturingHandler{}
+		logged.Logger = slog.New(handler)
+
+		err = logged.StreamSSE(context.Background(), "/events", map[string]string{"topic": "orders"}, func(event SSEEvent) error {
+			return nil
+		})
+		require.NoError(t, err)
+
+		require.Len(t, handler.records, 2)
+		assert.Equal(t, server.URL+"/events?topic=orders", handler.attrs(handler.records[0])["url"])
+		assert.Equal(t, "200", handler.attrs(handler.records[1])["status"])
+	})
+
+	t.Run("waits for the rate limiter", func(t *testing.T) {
+		limited, err := NewRESTClient(server.URL, AuthConfig{Type: NoAuth})
+		require.NoError(t, err)
+		limited.WithRateLimit(RateLimit{RequestsPerSecond: 0.1})
+
+		// The first stream uses up the only token
+		ignore := func(event SSEEvent) error { return nil }
+		require.NoError(t, limited.StreamSSE(context.Background(), "/events", map[string]string{"topic": "orders"}, ignore))
+
+		ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+		defer cancel()
+		err = limited.StreamSSE(ctx, "/events", map[string]string{"topic": "orders"}, ignore)
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+	})
+}
+
+func TestRESTClient_RecordAndReplay(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.Method {
+		case http.MethodGet:
+			w.Write([]byte(`{"id":1,"name":"John Doe"}`))
+		case http.MethodPost:
+			var user TestUser
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&user))
+			w.WriteHeader(http.StatusCreated)
+			fmt.Fprintf(w, `{"id":2,"name":%q}`, user.Name)
+		}
+	}))
+	baseURL := server.URL
+
+	dir := t.TempDir()
+	auth := AuthConfig{Type: APIKeyAuth, APIKey: "secret-key", KeyQuery: "api_key"}
+	ctx := context.Background()
+
+	recorder, err := NewRESTClient(baseURL, auth)
+	require.NoError(t, err)
+	_, err = recorder.RecordTo(dir)
+	require.NoError(t, err)
+
+	recordedGet, err := recorder.GET(ctx, "/users/1", map[string]string{"fields": "name"})
+	require.NoError(t, err)
+	recordedPost, err := recorder.POST(ctx, "/users", TestUser{Name: "Jane"})
+	require.NoError(t, err)
+
+	files, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	require.Len(t, files, 2)
+	for _, file := range files {
+		data, err := os.ReadFile(filepath.Join(dir, file.Name()))
+		require.NoError(t, err)
+		assert.NotContains(t, string(data), "secret-key", "credentials must not be recorded")
+	}
+
+	// Replay without the server
+	server.Close()
+
+	replayer, err := NewRESTClient(baseURL, auth)
+	require.NoError(t, err)
+	_, err = replayer.ReplayFrom(dir)
+	require.NoError(t, err)
+
+	t.Run("Replays recorded responses", func(t *testing.T) {
+		resp, err := replayer.GET(ctx, "/users/1", map[string]string{"fields": "name"})
+		require.NoError(t, err)
+		assert.Equal(t, recordedGet.StatusCode, resp.StatusCode)
+		assert.Equal(t, recordedGet.Body, resp.Body)
+		assert.Equal(t, "application/json", resp.ContentType)
+
+		resp, err = replayer.POST(ctx, "/users", TestUser{Name: "Jane"})
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusCreated, resp.StatusCode)
+		assert.Equal(t, recordedPost.Body, resp.Body)
+	})
+
+	t.Run("Different body has no fixture", func(t *testing.T) {
+		_, err := replayer.POST(ctx, "/users", TestUser{Name: "Someone else"})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "no recorded fixture for POST")
+	})
+
+	t.Run("Different URL has no fixture", func(t *testing.T) {
+		_, err := replayer.GET(ctx, "/users/1", nil)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "no recorded fixture for GET")
+	})
+
+	t.Run("Missing directory", func(t *testing.T) {
+		client, err := NewRESTClient(baseURL, auth)
+		require.NoError(t, err)
+		_, err = client.ReplayFrom(filepath.Join(dir, "missing"))
+		assert.Error(t, err)
+	})
+}
+
+func TestRESTClient_RecordToKeepsTransportOptions(t *testing.T) {
+	tokenServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"access_token":"token-1","token_type":"Bearer","expires_in":3600}`))
+	}))
+	defer tokenServer.Close()
+
+	apiServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "Bearer token-1", r.Header.Get("Authorization"))
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"id":1}`))
+	}))
+	defer apiServer.Close()
+
+	client, err := NewRESTClient(apiServer.URL, AuthConfig{
+		Type:         OAuth2Auth,
+		ClientID:     "client-id",
+		ClientSecret: "client-secret",
+		TokenURL:     tokenServer.URL,
+	})
+	require.NoError(t, err)
+
+	dir := t.TempDir()
+	_, err = client.RecordTo(dir)
+	require.NoError(t, err)
+	client.WithTransportTimeouts(TransportTimeouts{ResponseHeaderTimeout: 5 * time.Second})
+
+	_, err = client.GET(context.Background(), "/users/1", nil)
+	require.NoError(t, err)
+
+	// Still recording, for both plain and OAuth2 requests
+	files, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	assert.Len(t, files, 1)
+	assert.IsType(t, &recordingTransport{}, client.httpClient.Transport)
+	assert.IsType(t, &recordingTransport{}, client.oauth2Client.Transport)
+
+	// The timeout reached the transports beneath the recorder
+	plain, ok := (*transportSlot(&client.httpClient.Transport)).(*http.Transport)
+	require.True(t, ok)
+	assert.Equal(t, 5*time.Second, plain.ResponseHeaderTimeout)
+
+	oauthTransport, ok := (*transportSlot(&client.oauth2Client.Transport)).(*oauth2.Transport)
+	require.True(t, ok, "OAuth2 transport should be found beneath the recorder")
+	base, ok := oauthTransport.Base.(*http.Transport)
+	require.True(t, ok)
+	assert.Equal(t, 5*time.Second, base.ResponseHeaderTimeout)
+}
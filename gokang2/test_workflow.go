@@ -0,0 +1,243 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.temporal.io/sdk/activity"
+	"go.temporal.io/sdk/converter"
+	"go.temporal.io/sdk/testsuite"
+)
+
+// Test helper functions
+func newOrderTestEnvironment() *testsuite.TestWorkflowEnvironment {
+	testSuite := &testsuite.WorkflowTestSuite{}
+	env := testSuite.NewTestWorkflowEnvironment()
+
+	env.RegisterWorkflow(OrderProcessingWorkflow)
+	env.RegisterWorkflow(PaymentWorkflow)
+	env.RegisterWorkflow(ShippingWorkflow)
+
+	env.RegisterActivity(ValidateOrder)
+	env.RegisterActivity(GetCustomerAddress)
+	env.RegisterActivity(SendOrderConfirmation)
+	env.RegisterActivity(UpdateOrderStatus)
+	env.RegisterActivity(ChargeCustomer)
+	env.RegisterActivity(RecordPayment)
+	env.RegisterActivity(RefundPayment)
+	env.RegisterActivity(CreateShipment)
+	env.RegisterActivity(SchedulePickup)
+	env.RegisterActivity(NotifyCustomer)
+	env.RegisterActivity(CancelShipment)
+
+	return env
+}
+
+func testOrderRequest() OrderRequest {
+	return OrderRequest{
+		OrderID:    "ORDER-12345",
+		CustomerID: "CUST-001",
+		Amount:     99.99,
+		ProductID:  "PROD-ABC",
+	}
+}
+
+func TestOrderProcessingWorkflow_Success(t *testing.T) {
+	env := newOrderTestEnvironment()
+
+	env.OnActivity(ChargeCustomer, mock.Anything, "ORDER-12345", "CUST-001", 99.99).Return("PAY_1", nil)
+	env.OnActivity(CreateShipment, mock.Anything, "ORDER-12345", mock.Anything).Return("TRACK_1", nil)
+	env.OnActivity(RefundPayment, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil).Maybe()
+	env.OnActivity(CancelShipment, mock.Anything, mock.Anything, mock.Anything).Return(nil).Maybe()
+
+	env.ExecuteWorkflow(OrderProcessingWorkflow, testOrderRequest())
+
+	require.True(t, env.IsWorkflowCompleted())
+	require.NoError(t, env.GetWorkflowError())
+
+	var result string
+	err := env.GetWorkflowResult(&result)
+	assert.NoError(t, err)
+	assert.Contains(t, result, "PAY_1")
+	assert.Contains(t, result, "TRACK_1")
+
+	env.AssertNotCalled(t, "RefundPayment", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	env.AssertNotCalled(t, "CancelShipment", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestOrderProcessingWorkflow_ShippingFailureRefundsPayment(t *testing.T) {
+	env := newOrderTestEnvironment()
+
+	env.OnActivity(ChargeCustomer, mock.Anything, "ORDER-12345", "CUST-001", 99.99).Return("PAY_1", nil)
+	env.OnActivity(CreateShipment, mock.Anything, "ORDER-12345", mock.Anything).Return("", errors.New("carrier unavailable"))
+	env.OnActivity(RefundPayment, mock.Anything, "ORDER-12345", "PAY_1", 99.99).Return(nil).Once()
+	env.OnActivity(CancelShipment, mock.Anything, mock.Anything, mock.Anything).Return(nil).Maybe()
+
+	env.ExecuteWorkflow(OrderProcessingWorkflow, testOrderRequest())
+
+	require.True(t, env.IsWorkflowCompleted())
+	err := env.GetWorkflowError()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "shipping workflow failed")
+
+	env.AssertExpectations(t)
+	env.AssertNotCalled(t, "CancelShipment", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestOrderProcessingWorkflow_ConfirmationFailureCompensatesInReverse(t *testing.T) {
+	env := newOrderTestEnvironment()
+
+	var compensationOrder []string
+
+	env.OnActivity(ChargeCustomer, mock.Anything, "ORDER-12345", "CUST-001", 99.99).Return("PAY_1", nil)
+	env.OnActivity(CreateShipment, mock.Anything, "ORDER-12345", mock.Anything).Return("TRACK_1", nil)
+	env.OnActivity(SendOrderConfirmation, mock.Anything, mock.Anything, mock.Anything).Return(errors.New("mail server down"))
+	env.OnActivity(CancelShipment, mock.Anything, "ORDER-12345", "TRACK_1").Return(func(_ context.Context, _, _ string) error {
+		compensationOrder = append(compensationOrder, "CancelShipment")
+		return nil
+	}).Once()
+	env.OnActivity(RefundPayment, mock.Anything, "ORDER-12345", "PAY_1", 99.99).Return(func(_ context.Context, _, _ string, _ float64) error {
+		compensationOrder = append(compensationOrder, "RefundPayment")
+		return nil
+	}).Once()
+
+	env.ExecuteWorkflow(OrderProcessingWorkflow, testOrderRequest())
+
+	require.True(t, env.IsWorkflowCompleted())
+	require.Error(t, env.GetWorkflowError())
+
+	env.AssertExpectations(t)
+	assert.Equal(t, []string{"CancelShipment", "RefundPayment"}, compensationOrder)
+}
+
+func TestOrderProcessingWorkflow_GetOrderStatusQuery(t *testing.T) {
+	env := newOrderTestEnvironment()
+
+	env.OnActivity(ChargeCustomer, mock.Anything, "ORDER-12345", "CUST-001", 99.99).Return("PAY_1", nil)
+	env.OnActivity(CreateShipment, mock.Anything, "ORDER-12345", mock.Anything).Return("TRACK_1", nil)
+
+	// Query the status as each order status update starts
+	var observed []string
+	env.SetOnActivityStartedListener(func(activityInfo *activity.Info, ctx context.Context, args converter.EncodedValues) {
+		if activityInfo.ActivityType.Name != "UpdateOrderStatus" {
+			return
+		}
+
+		value, err := env.QueryWorkflow(GetOrderStatusQuery)
+		require.NoError(t, err)
+
+		var status string
+		require.NoError(t, value.Get(&status))
+		observed = append(observed, status)
+	})
+
+	env.ExecuteWorkflow(OrderProcessingWorkflow, testOrderRequest())
+
+	require.True(t, env.IsWorkflowCompleted())
+	require.NoError(t, env.GetWorkflowError())
+	assert.Equal(t, []string{"VALIDATING", "PROCESSING", "COMPLETED"}, observed)
+
+	value, err := env.QueryWorkflow(GetOrderStatusQuery)
+	require.NoError(t, err)
+
+	var status string
+	require.NoError(t, value.Get(&status))
+	assert.Equal(t, "COMPLETED", status)
+}
+
+func TestOrderProcessingWorkflow_CancelBeforeShipping(t *testing.T) {
+	env := newOrderTestEnvironment()
+
+	// Send the cancel signal while the order is still being validated
+	env.SetOnActivityStartedListener(func(activityInfo *activity.Info, ctx context.Context, args converter.EncodedValues) {
+		if activityInfo.ActivityType.Name == "ValidateOrder" {
+			env.SignalWorkflow(CancelOrderSignal, "customer request")
+		}
+	})
+
+	env.ExecuteWorkflow(OrderProcessingWorkflow, testOrderRequest())
+
+	require.True(t, env.IsWorkflowCompleted())
+	require.NoError(t, env.GetWorkflowError())
+
+	var result string
+	require.NoError(t, env.GetWorkflowResult(&result))
+	assert.Contains(t, result, "Order cancelled")
+
+	value, err := env.QueryWorkflow(GetOrderStatusQuery)
+	require.NoError(t, err)
+
+	var status string
+	require.NoError(t, value.Get(&status))
+	assert.Equal(t, "CANCELLED", status)
+}
+
+func TestOrderProcessingWorkflow_CancelAfterShippingIgnored(t *testing.T) {
+	env := newOrderTestEnvironment()
+
+	env.OnActivity(ChargeCustomer, mock.Anything, "ORDER-12345", "CUST-001", 99.99).Return("PAY_1", nil)
+	env.OnActivity(CreateShipment, mock.Anything, "ORDER-12345", mock.Anything).Return("TRACK_1", nil)
+
+	// Send the cancel signal once shipping is underway
+	env.SetOnActivityStartedListener(func(activityInfo *activity.Info, ctx context.Context, args converter.EncodedValues) {
+		if activityInfo.ActivityType.Name == "CreateShipment" {
+			env.SignalWorkflow(CancelOrderSignal, "customer request")
+		}
+	})
+
+	env.ExecuteWorkflow(OrderProcessingWorkflow, testOrderRequest())
+
+	require.True(t, env.IsWorkflowCompleted())
+	require.NoError(t, env.GetWorkflowError())
+
+	var result string
+	require.NoError(t, env.GetWorkflowResult(&result))
+	assert.Contains(t, result, "Order processed successfully")
+
+	value, err := env.QueryWorkflow(GetOrderStatusQuery)
+	require.NoError(t, err)
+
+	var status string
+	require.NoError(t, value.Get(&status))
+	assert.Equal(t, "COMPLETED", status)
+}
+
+func TestPaymentWorkflow_ChargeRetryReusesPaymentID(t *testing.T) {
+	env := newOrderTestEnvironment()
+
+	// Run the real activity on every attempt but fail the first one to force a retry
+	var paymentIDs []string
+	env.OnActivity(ChargeCustomer, mock.Anything, "ORDER-12345", "CUST-001", 99.99).Return(
+		func(ctx context.Context, orderID, customerID string, amount float64) (string, error) {
+			paymentID, err := ChargeCustomer(ctx, orderID, customerID, amount)
+			if err != nil {
+				return "", err
+			}
+			paymentIDs = append(paymentIDs, paymentID)
+			if len(paymentIDs) == 1 {
+				return "", errors.New("payment gateway timeout")
+			}
+			return paymentID, nil
+		})
+
+	env.ExecuteWorkflow(PaymentWorkflow, PaymentRequest{
+		OrderID:    "ORDER-12345",
+		CustomerID: "CUST-001",
+		Amount:     99.99,
+	})
+
+	require.True(t, env.IsWorkflowCompleted())
+	require.NoError(t, env.GetWorkflowError())
+
+	var paymentID string
+	require.NoError(t, env.GetWorkflowResult(&paymentID))
+
+	require.Len(t, paymentIDs, 2)
+	assert.Equal(t, paymentIDs[0], paymentIDs[1])
+	assert.Equal(t, paymentIDs[1], paymentID)
+	assert.Equal(t, "PAY_ORDER-12345_v1", paymentID)
+}
@@ -77,16 +77,26 @@ func UpdateOrderStatus(ctx context.Context, orderID, status string) error {
 }
 
 // Payment Activities
-func ChargeCustomer(ctx context.Context, customerID string, amount float64) (string, error) {
+// paymentIDVersion is bumped when the payment ID scheme changes
+const paymentIDVersion = 1
+
+// ChargeCustomer charges the customer for an order. The payment ID is derived from the
+// order ID so activity retries reuse it and the payment provider can deduplicate charges.
+func ChargeCustomer(ctx context.Context, orderID, customerID string, amount float64) (string, error) {
 	logger := activity.GetLogger(ctx)
-	logger.Info("Processing payment", "customer_id", customerID, "amount", amount)
+	paymentID := paymentIDForOrder(orderID)
+	logger.Info("Processing payment", "order_id", orderID, "customer_id", customerID, "amount", amount, "payment_id", paymentID)
 
 	// Simulate payment processing
 	time.Sleep(500 * time.Millisecond)
-	paymentID := fmt.Sprintf("PAY_%d", time.Now().Unix())
 	return paymentID, nil
 }
 
+// paymentIDForOrder returns the deterministic payment ID for an order
+func paymentIDForOrder(orderID string) string {
+	return fmt.Sprintf("PAY_%s_v%d", orderID, paymentIDVersion)
+}
+
 func RecordPayment(ctx context.Context, orderID, paymentID string, amount float64) error {
 	logger := activity.GetLogger(ctx)
 	logger.Info("Recording payment", "order_id", orderID, "payment_id", paymentID)
@@ -96,6 +106,15 @@ func RecordPayment(ctx context.Context, orderID, paymentID string, amount float6
 	return nil
 }
 
+func RefundPayment(ctx context.Context, orderID, paymentID string, amount float64) error {
+	logger := activity.GetLogger(ctx)
+	logger.Info("Refunding payment", "order_id", orderID, "payment_id", paymentID, "amount", amount)
+
+	// Simulate refund processing
+	time.Sleep(300 * time.Millisecond)
+	return nil
+}
+
 // Shipping Activities
 func CreateShipment(ctx context.Context, orderID, address string) (string, error) {
 	logger := activity.GetLogger(ctx)
@@ -125,6 +144,49 @@ func NotifyCustomer(ctx context.Context, customerID, trackingNumber string) erro
 	return nil
 }
 
+func CancelShipment(ctx context.Context, orderID, trackingNumber string) error {
+	logger := activity.GetLogger(ctx)
+	logger.Info("Cancelling shipment", "order_id", orderID, "tracking_number", trackingNumber)
+
+	// Simulate shipment cancellation
+	time.Sleep(200 * time.Millisecond)
+	return nil
+}
+
+// Saga Compensation
+
+// Compensations is a stack of compensating activities registered as saga steps succeed
+type Compensations struct {
+	steps []compensationStep
+}
+
+type compensationStep struct {
+	activity interface{}
+	args     []interface{}
+}
+
+// Add registers a compensating activity for a completed step
+func (c *Compensations) Add(activity interface{}, args ...interface{}) {
+	c.steps = append(c.steps, compensationStep{activity: activity, args: args})
+}
+
+// Compensate runs the registered compensating activities in reverse order.
+// Failures are logged and the remaining compensations still run.
+func (c *Compensations) Compensate(ctx workflow.Context) {
+	logger := workflow.GetLogger(ctx)
+
+	// Compensate even if the workflow itself was cancelled
+	ctx, _ = workflow.NewDisconnectedContext(ctx)
+
+	for i := len(c.steps) - 1; i >= 0; i-- {
+		step := c.steps[i]
+		err := workflow.ExecuteActivity(ctx, step.activity, step.args...).Get(ctx, nil)
+		if err != nil {
+			logger.Error("Compensation failed", "step", i, "error", err)
+		}
+	}
+}
+
 // Child Workflow: Payment Processing
 func PaymentWorkflow(ctx workflow.Context, request PaymentRequest) (string, error) {
 	logger := workflow.GetLogger(ctx)
@@ -144,7 +206,7 @@ func PaymentWorkflow(ctx workflow.Context, request PaymentRequest) (string, erro
 
 	// Charge customer
 	var paymentID string
-	err := workflow.ExecuteActivity(ctx, ChargeCustomer, request.CustomerID, request.Amount).Get(ctx, &paymentID)
+	err := workflow.ExecuteActivity(ctx, ChargeCustomer, request.OrderID, request.CustomerID, request.Amount).Get(ctx, &paymentID)
 	if err != nil {
 		return "", fmt.Errorf("failed to charge customer: %w", err)
 	}
@@ -199,6 +261,13 @@ func ShippingWorkflow(ctx workflow.Context, request ShippingRequest) (string, er
 	return trackingNumber, nil
 }
 
+const (
+	// GetOrderStatusQuery is the query type returning the order workflow's current status
+	GetOrderStatusQuery = "GetOrderStatus"
+	// CancelOrderSignal is the signal name for cancelling an order before shipping starts
+	CancelOrderSignal = "CancelOrder"
+)
+
 // Parent Workflow: Order Processing
 func OrderProcessingWorkflow(ctx workflow.Context, request OrderRequest) (string, error) {
 	logger := workflow.GetLogger(ctx)
@@ -216,8 +285,19 @@ func OrderProcessingWorkflow(ctx workflow.Context, request OrderRequest) (string
 	}
 	ctx = workflow.WithActivityOptions(ctx, activityOptions)
 
+	// Track the current status so operators can query it mid-execution
+	currentStatus := "VALIDATING"
+	err := workflow.SetQueryHandler(ctx, GetOrderStatusQuery, func() (string, error) {
+		return currentStatus, nil
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to register status query handler: %w", err)
+	}
+
+	cancelChannel := workflow.GetSignalChannel(ctx, CancelOrderSignal)
+
 	// Step 1: Update status to validating
-	err := workflow.ExecuteActivity(ctx, UpdateOrderStatus, request.OrderID, "VALIDATING").Get(ctx, nil)
+	err = workflow.ExecuteActivity(ctx, UpdateOrderStatus, request.OrderID, "VALIDATING").Get(ctx, nil)
 	if err != nil {
 		return "", fmt.Errorf("failed to update order status: %w", err)
 	}
@@ -226,11 +306,13 @@ func OrderProcessingWorkflow(ctx workflow.Context, request OrderRequest) (string
 	var validationResult string
 	err = workflow.ExecuteActivity(ctx, ValidateOrder, request).Get(ctx, &validationResult)
 	if err != nil {
+		currentStatus = "INVALID"
 		workflow.ExecuteActivity(ctx, UpdateOrderStatus, request.OrderID, "INVALID").Get(ctx, nil)
 		return "", fmt.Errorf("order validation failed: %w", err)
 	}
 
 	if validationResult != "VALID" {
+		currentStatus = "INVALID"
 		workflow.ExecuteActivity(ctx, UpdateOrderStatus, request.OrderID, "INVALID").Get(ctx, nil)
 		return "Order validation failed", nil
 	}
@@ -243,11 +325,24 @@ func OrderProcessingWorkflow(ctx workflow.Context, request OrderRequest) (string
 	}
 
 	// Step 4: Update status to processing
+	currentStatus = "PROCESSING"
 	err = workflow.ExecuteActivity(ctx, UpdateOrderStatus, request.OrderID, "PROCESSING").Get(ctx, nil)
 	if err != nil {
 		return "", fmt.Errorf("failed to update order status: %w", err)
 	}
 
+	// Honor a cancel request received before shipping starts
+	var cancelReason string
+	if cancelChannel.ReceiveAsync(&cancelReason) {
+		logger.Info("Order cancelled before shipping", "order_id", request.OrderID, "reason", cancelReason)
+		currentStatus = "CANCELLED"
+		err = workflow.ExecuteActivity(ctx, UpdateOrderStatus, request.OrderID, "CANCELLED").Get(ctx, nil)
+		if err != nil {
+			return "", fmt.Errorf("failed to update order status: %w", err)
+		}
+		return fmt.Sprintf("Order cancelled: %s", cancelReason), nil
+	}
+
 	// Step 5: Execute child workflows in parallel
 	childWorkflowOptions := workflow.ChildWorkflowOptions{
 		WorkflowExecutionTimeout: 10 * time.Minute,
@@ -277,28 +372,54 @@ func OrderProcessingWorkflow(ctx workflow.Context, request OrderRequest) (string
 		Address:    customerAddress,
 	})
 
-	// Wait for both child workflows to complete
+	// Shipping has begun, so later cancel requests can no longer be honored
+	workflow.Go(ctx, func(ctx workflow.Context) {
+		for {
+			var reason string
+			cancelChannel.Receive(ctx, &reason)
+			logger.Info("Ignoring cancel request, shipping has already begun", "order_id", request.OrderID, "reason", reason)
+		}
+	})
+
+	// Wait for both child workflows to complete, registering compensations as they succeed
 	var paymentID, trackingNumber string
+	var compensations Compensations
 
-	err = paymentFuture.Get(ctx, &paymentID)
-	if err != nil {
+	paymentErr := paymentFuture.Get(ctx, &paymentID)
+	if paymentErr == nil {
+		compensations.Add(RefundPayment, request.OrderID, paymentID, request.Amount)
+	}
+
+	shippingErr := shippingFuture.Get(ctx, &trackingNumber)
+	if shippingErr == nil {
+		compensations.Add(CancelShipment, request.OrderID, trackingNumber)
+	}
+
+	if paymentErr != nil {
+		compensations.Compensate(ctx)
+		currentStatus = "FAILED"
 		workflow.ExecuteActivity(ctx, UpdateOrderStatus, request.OrderID, "FAILED").Get(ctx, nil)
-		return "", fmt.Errorf("payment workflow failed: %w", err)
+		return "", fmt.Errorf("payment workflow failed: %w", paymentErr)
 	}
 
-	err = shippingFuture.Get(ctx, &trackingNumber)
-	if err != nil {
+	if shippingErr != nil {
+		compensations.Compensate(ctx)
+		currentStatus = "FAILED"
 		workflow.ExecuteActivity(ctx, UpdateOrderStatus, request.OrderID, "FAILED").Get(ctx, nil)
-		return "", fmt.Errorf("shipping workflow failed: %w", err)
+		return "", fmt.Errorf("shipping workflow failed: %w", shippingErr)
 	}
 
 	// Step 6: Send confirmation
 	err = workflow.ExecuteActivity(ctx, SendOrderConfirmation, request.OrderID, request.CustomerID).Get(ctx, nil)
 	if err != nil {
+		compensations.Compensate(ctx)
+		currentStatus = "FAILED"
+		workflow.ExecuteActivity(ctx, UpdateOrderStatus, request.OrderID, "FAILED").Get(ctx, nil)
 		return "", fmt.Errorf("failed to send confirmation: %w", err)
 	}
 
 	// Step 7: Update final status
+	currentStatus = "COMPLETED"
 	err = workflow.ExecuteActivity(ctx, UpdateOrderStatus, request.OrderID, "COMPLETED").Get(ctx, nil)
 	if err != nil {
 		return "", fmt.Errorf("failed to update final status: %w", err)
@@ -338,6 +459,8 @@ func main() {
 	w.RegisterActivity(CreateShipment)
 	w.RegisterActivity(SchedulePickup)
 	w.RegisterActivity(NotifyCustomer)
+	w.RegisterActivity(RefundPayment)
+	w.RegisterActivity(CancelShipment)
 
 	// Start worker in goroutine
 	go func() {
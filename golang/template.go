@@ -9,6 +9,7 @@ import (
 	"net/http"
 	"net/url"
 	"os"
+	"sort"
 	"strconv"
 	"strings"
 	"time"
@@ -34,6 +35,10 @@ type Config struct {
 
 	// Default Headers
 	DefaultHeaders map[string]string `json:"default_headers"`
+
+	// StrictContentType stops Execute from defaulting Content-Type to application/json
+	// for requests with a body, leaving it to the caller's headers
+	StrictContentType bool `json:"strict_content_type"`
 }
 
 type BasicAuthConfig struct {
@@ -62,11 +67,21 @@ func NewRestClient(configPath string) (*RestClient, error) {
 		return nil, fmt.Errorf("failed to load config: %w", err)
 	}
 
+	return NewRestClientFromConfig(config)
+}
+
+// NewRestClientFromConfig creates a REST client from an in-memory config, e.g. one fetched
+// from a secret manager. Unset timeout and auth type get their defaults; environment
+// variables are not applied, so use loadConfigFromReader for that.
+func NewRestClientFromConfig(config Config) (*RestClient, error) {
+	applyConfigDefaults(&config)
+
 	client := &RestClient{
 		config: config,
 	}
 
 	// Setup HTTP client based on auth type
+	var err error
 	switch strings.ToLower(config.AuthType) {
 	case "oauth2":
 		client.httpClient, err = client.setupOAuth2Client()
@@ -82,23 +97,78 @@ func NewRestClient(configPath string) (*RestClient, error) {
 	return client, nil
 }
 
+// LoadClients creates a REST client for each service in a JSON file mapping service
+// names to configs, e.g. {"users": {"base_url": "..."}, "billing": {...}}.
+// Environment variables are not applied, since they would override every service alike.
+func LoadClients(path string) (map[string]*RestClient, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read clients config: %w", err)
+	}
+
+	var configs map[string]Config
+	if err := json.Unmarshal(data, &configs); err != nil {
+		return nil, fmt.Errorf("failed to decode clients config: %w", err)
+	}
+
+	// Build in name order so the same file always reports the same failure
+	names := make([]string, 0, len(configs))
+	for name := range configs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	clients := make(map[string]*RestClient, len(configs))
+	for _, name := range names {
+		config := configs[name]
+		if config.BaseURL == "" {
+			return nil, fmt.Errorf("service %q: base_url is required", name)
+		}
+
+		client, err := NewRestClientFromConfig(config)
+		if err != nil {
+			return nil, fmt.Errorf("service %q: %w", name, err)
+		}
+		clients[name] = client
+	}
+
+	return clients, nil
+}
+
 // loadConfig loads configuration from JSON file or environment variables
 func loadConfig(configPath string) (Config, error) {
-	var config Config
-
 	// Try to load from file first
 	if configPath != "" {
 		file, err := os.Open(configPath)
 		if err == nil {
 			defer file.Close()
-			decoder := json.NewDecoder(file)
-			if err := decoder.Decode(&config); err != nil {
-				return config, fmt.Errorf("failed to decode config file: %w", err)
-			}
+			return loadConfigFromReader(file)
 		}
 	}
 
-	// Override with environment variables if present
+	var config Config
+	applyEnvOverrides(&config)
+	applyConfigDefaults(&config)
+
+	return config, nil
+}
+
+// loadConfigFromReader decodes JSON configuration from r, then applies
+// environment variable overrides and defaults as loadConfig does
+func loadConfigFromReader(r io.Reader) (Config, error) {
+	var config Config
+	if err := json.NewDecoder(r).Decode(&config); err != nil {
+		return config, fmt.Errorf("failed to decode config: %w", err)
+	}
+
+	applyEnvOverrides(&config)
+	applyConfigDefaults(&config)
+
+	return config, nil
+}
+
+// applyEnvOverrides overrides config fields with environment variables if present
+func applyEnvOverrides(config *Config) {
 	if val := os.Getenv("REST_BASE_URL"); val != "" {
 		config.BaseURL = val
 	}
@@ -128,16 +198,16 @@ func loadConfig(configPath string) (Config, error) {
 	if val := os.Getenv("REST_BEARER_TOKEN"); val != "" {
 		config.BearerToken = val
 	}
+}
 
-	// Set defaults
+// applyConfigDefaults fills in the timeout and auth type when unset
+func applyConfigDefaults(config *Config) {
 	if config.Timeout == 0 {
 		config.Timeout = 30
 	}
 	if config.AuthType == "" {
 		config.AuthType = "none"
 	}
-
-	return config, nil
 }
 
 // setupOAuth2Client creates an HTTP client with OAuth2 authentication
@@ -171,6 +241,7 @@ type Request struct {
 	Path    string
 	Headers map[string]string
 	Body    interface{}
+	Timeout time.Duration // Overrides the config-level timeout for this request when set
 }
 
 // Response represents an HTTP response
@@ -183,7 +254,7 @@ type Response struct {
 // Execute performs the HTTP request
 func (c *RestClient) Execute(req Request) (*Response, error) {
 	// Build full URL
-	fullURL := strings.TrimRight(c.config.BaseURL, "/") + "/" + strings.TrimLeft(req.Path, "/")
+	fullURL := joinURL(c.config.BaseURL, req.Path)
 
 	// Prepare request body
 	var bodyReader io.Reader
@@ -211,8 +282,8 @@ func (c *RestClient) Execute(req Request) (*Response, error) {
 		httpReq.Header.Set(k, v)
 	}
 
-	// Set Content-Type for JSON if body is present
-	if req.Body != nil && httpReq.Header.Get("Content-Type") == "" {
+	// Set Content-Type for JSON if body is present, unless the caller must choose it
+	if req.Body != nil && !c.config.StrictContentType && httpReq.Header.Get("Content-Type") == "" {
 		httpReq.Header.Set("Content-Type", "application/json")
 	}
 
@@ -221,8 +292,19 @@ func (c *RestClient) Execute(req Request) (*Response, error) {
 		return nil, fmt.Errorf("failed to apply authentication: %w", err)
 	}
 
+	// Use a copy of the client when the request overrides the timeout
+	httpClient := c.httpClient
+	if req.Timeout > 0 {
+		httpClient = &http.Client{
+			Transport:     c.httpClient.Transport,
+			CheckRedirect: c.httpClient.CheckRedirect,
+			Jar:           c.httpClient.Jar,
+			Timeout:       req.Timeout,
+		}
+	}
+
 	// Execute request
-	httpResp, err := c.httpClient.Do(httpReq)
+	httpResp, err := httpClient.Do(httpReq)
 	if err != nil {
 		return nil, fmt.Errorf("failed to execute HTTP request: %w", err)
 	}
@@ -241,6 +323,21 @@ func (c *RestClient) Execute(req Request) (*Response, error) {
 	}, nil
 }
 
+// joinURL joins a base URL and a path with exactly one slash between them
+func joinURL(base, path string) string {
+	base = strings.TrimRight(base, "/")
+	path = strings.TrimLeft(path, "/")
+
+	switch {
+	case path == "":
+		return base
+	case strings.HasPrefix(path, "?"):
+		return base + path
+	default:
+		return base + "/" + path
+	}
+}
+
 // applyAuth applies the configured authentication to the request
 func (c *RestClient) applyAuth(req *http.Request) error {
 	switch strings.ToLower(c.config.AuthType) {
@@ -304,6 +401,83 @@ func (c *RestClient) Delete(path string, headers map[string]string) (*Response,
 	})
 }
 
+// RetryPolicy controls GetWithRetry backoff
+type RetryPolicy struct {
+	MaxRetries     int
+	InitialBackoff time.Duration
+	Multiplier     float64
+	MaxBackoff     time.Duration
+}
+
+// GetWithRetry performs a GET, retrying network errors, 5xx and 429 responses with exponential backoff.
+// A Retry-After response header takes precedence over the computed backoff.
+func (c *RestClient) GetWithRetry(path string, headers map[string]string, policy RetryPolicy) (*Response, error) {
+	backoff := policy.InitialBackoff
+	if backoff <= 0 {
+		backoff = time.Second
+	}
+	multiplier := policy.Multiplier
+	if multiplier <= 0 {
+		multiplier = 2.0
+	}
+
+	var lastResp *Response
+	var lastErr error
+
+	for attempt := 0; attempt <= policy.MaxRetries; attempt++ {
+		resp, err := c.Get(path, headers)
+		if err == nil && !isRetryableStatus(resp.StatusCode) {
+			// Success, or a client error that retrying won't fix
+			return resp, nil
+		}
+
+		lastResp, lastErr = resp, err
+		if attempt == policy.MaxRetries {
+			break
+		}
+
+		wait := backoff
+		if resp != nil {
+			if retryAfter, ok := parseRetryAfter(resp.Headers.Get("Retry-After")); ok {
+				wait = retryAfter
+			}
+		}
+		time.Sleep(wait)
+
+		backoff = time.Duration(float64(backoff) * multiplier)
+		if policy.MaxBackoff > 0 && backoff > policy.MaxBackoff {
+			backoff = policy.MaxBackoff
+		}
+	}
+
+	if lastErr != nil {
+		return nil, fmt.Errorf("failed after %d retries: %w", policy.MaxRetries, lastErr)
+	}
+	return lastResp, fmt.Errorf("failed after %d retries: status %d", policy.MaxRetries, lastResp.StatusCode)
+}
+
+// isRetryableStatus reports whether a status code is worth retrying (5xx and 429)
+func isRetryableStatus(statusCode int) bool {
+	return statusCode >= 500 || statusCode == http.StatusTooManyRequests
+}
+
+// parseRetryAfter parses a Retry-After value given as delay seconds or an HTTP date
+func parseRetryAfter(value string) (time.Duration, bool) {
+	if value == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(value); err == nil && seconds >= 0 {
+		return time.Duration(seconds) * time.Second, true
+	}
+	if date, err := http.ParseTime(value); err == nil {
+		if wait := time.Until(date); wait > 0 {
+			return wait, true
+		}
+		return 0, true
+	}
+	return 0, false
+}
+
 // Example usage
 func main() {
 	// Create client from config file
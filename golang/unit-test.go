@@ -1,6 +1,7 @@
 package main
 
 import (
+	"bytes"
 	"encoding/json"
 	"fmt"
 	"net/http"
@@ -89,6 +90,33 @@ func TestConfig(t *testing.T) {
 		}
 	})
 
+	t.Run("LoadConfigFromReader", func(t *testing.T) {
+		os.Setenv("REST_BEARER_TOKEN", "env-token-456")
+		defer os.Unsetenv("REST_BEARER_TOKEN")
+
+		buf := bytes.NewBufferString(`{"base_url": "https://reader.example.com", "auth_type": "bearer", "bearer_token": "file-token"}`)
+		config, err := loadConfigFromReader(buf)
+		if err != nil {
+			t.Fatalf("Failed to load config from reader: %v", err)
+		}
+
+		if config.BaseURL != "https://reader.example.com" {
+			t.Errorf("Expected BaseURL from reader, got %s", config.BaseURL)
+		}
+		if config.BearerToken != "env-token-456" {
+			t.Errorf("Expected BearerToken overridden from env, got %s", config.BearerToken)
+		}
+		if config.Timeout != 30 {
+			t.Errorf("Expected default timeout 30, got %d", config.Timeout)
+		}
+	})
+
+	t.Run("LoadConfigFromInvalidReader", func(t *testing.T) {
+		if _, err := loadConfigFromReader(strings.NewReader("{invalid json")); err == nil {
+			t.Error("Expected error for invalid JSON")
+		}
+	})
+
 	t.Run("DefaultValues", func(t *testing.T) {
 		config, err := loadConfig("nonexistent.json")
 		if err != nil {
@@ -182,6 +210,100 @@ func TestRestClientCreation(t *testing.T) {
 			t.Errorf("Expected no auth, got %s", client.config.AuthType)
 		}
 	})
+
+	t.Run("CreateFromConfig", func(t *testing.T) {
+		client, err := NewRestClientFromConfig(Config{
+			BaseURL:     "https://memory.example.com",
+			AuthType:    "bearer",
+			BearerToken: "memory-token",
+		})
+		if err != nil {
+			t.Fatalf("Failed to create client: %v", err)
+		}
+
+		if client.config.BaseURL != "https://memory.example.com" {
+			t.Errorf("Expected BaseURL from config, got %s", client.config.BaseURL)
+		}
+		if client.httpClient.Timeout != 30*time.Second {
+			t.Errorf("Expected default timeout 30s, got %v", client.httpClient.Timeout)
+		}
+	})
+}
+
+// TestLoadClients tests creating named clients from a single config file
+func TestLoadClients(t *testing.T) {
+	t.Run("LoadTwoServices", func(t *testing.T) {
+		configs := map[string]Config{
+			"users": {
+				BaseURL:     "https://users.example.com",
+				AuthType:    "bearer",
+				BearerToken: "users-token",
+			},
+			"billing": {
+				BaseURL:  "https://billing.example.com",
+				Timeout:  10,
+				AuthType: "basic",
+				BasicAuth: BasicAuthConfig{
+					Username: "billing",
+					Password: "secret",
+				},
+			},
+		}
+
+		configData, _ := json.Marshal(configs)
+		tmpFile := "test_clients_config.json"
+		os.WriteFile(tmpFile, configData, 0644)
+		defer os.Remove(tmpFile)
+
+		clients, err := LoadClients(tmpFile)
+		if err != nil {
+			t.Fatalf("Failed to load clients: %v", err)
+		}
+
+		if len(clients) != 2 {
+			t.Fatalf("Expected 2 clients, got %d", len(clients))
+		}
+		for name, config := range configs {
+			client, ok := clients[name]
+			if !ok {
+				t.Fatalf("Expected client for service %s", name)
+			}
+			if client.config.BaseURL != config.BaseURL {
+				t.Errorf("Expected %s BaseURL %s, got %s", name, config.BaseURL, client.config.BaseURL)
+			}
+			if client.httpClient == nil {
+				t.Errorf("HTTP client for %s should not be nil", name)
+			}
+		}
+
+		if clients["users"].config.Timeout != 30 {
+			t.Errorf("Expected default timeout 30, got %d", clients["users"].config.Timeout)
+		}
+		if clients["billing"].httpClient.Timeout != 10*time.Second {
+			t.Errorf("Expected billing timeout 10s, got %v", clients["billing"].httpClient.Timeout)
+		}
+	})
+
+	t.Run("ErrorIdentifiesService", func(t *testing.T) {
+		configData := []byte(`{"users": {"base_url": "https://users.example.com"}, "orders": {"auth_type": "none"}}`)
+		tmpFile := "test_clients_invalid_config.json"
+		os.WriteFile(tmpFile, configData, 0644)
+		defer os.Remove(tmpFile)
+
+		_, err := LoadClients(tmpFile)
+		if err == nil {
+			t.Fatal("Expected error for service without base_url")
+		}
+		if !strings.Contains(err.Error(), `"orders"`) {
+			t.Errorf("Expected error to name the orders service, got: %v", err)
+		}
+	})
+
+	t.Run("MissingFile", func(t *testing.T) {
+		if _, err := LoadClients("nonexistent_clients.json"); err == nil {
+			t.Error("Expected error for missing file")
+		}
+	})
 }
 
 // TestHTTPMethods tests all HTTP methods with mock server
@@ -570,6 +692,63 @@ func TestHeaders(t *testing.T) {
 	})
 }
 
+// TestStrictContentType tests that StrictContentType disables the JSON Content-Type default
+func TestStrictContentType(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, present := r.Header["Content-Type"]
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"present":      present,
+			"content_type": r.Header.Get("Content-Type"),
+		})
+	}))
+	defer server.Close()
+
+	newClient := func(strict bool) *RestClient {
+		client, err := NewRestClientFromConfig(Config{
+			BaseURL:           server.URL,
+			Timeout:           30,
+			AuthType:          "none",
+			StrictContentType: strict,
+		})
+		if err != nil {
+			t.Fatalf("Failed to create client: %v", err)
+		}
+		return client
+	}
+
+	post := func(client *RestClient, headers map[string]string) (bool, string) {
+		resp, err := client.Post("/test", map[string]string{"name": "test"}, headers)
+		if err != nil {
+			t.Fatalf("Request failed: %v", err)
+		}
+		var result struct {
+			Present     bool   `json:"present"`
+			ContentType string `json:"content_type"`
+		}
+		json.Unmarshal(resp.Body, &result)
+		return result.Present, result.ContentType
+	}
+
+	t.Run("DefaultAddsJSON", func(t *testing.T) {
+		if _, contentType := post(newClient(false), nil); contentType != "application/json" {
+			t.Errorf("Expected Content-Type 'application/json', got '%s'", contentType)
+		}
+	})
+
+	t.Run("StrictAddsNothing", func(t *testing.T) {
+		if present, contentType := post(newClient(true), nil); present {
+			t.Errorf("Expected no Content-Type, got '%s'", contentType)
+		}
+	})
+
+	t.Run("StrictKeepsExplicitType", func(t *testing.T) {
+		headers := map[string]string{"Content-Type": "application/xml"}
+		if _, contentType := post(newClient(true), headers); contentType != "application/xml" {
+			t.Errorf("Expected Content-Type 'application/xml', got '%s'", contentType)
+		}
+	})
+}
+
 // TestErrorHandling tests error scenarios
 func TestErrorHandling(t *testing.T) {
 	t.Run("InvalidConfig", func(t *testing.T) {
@@ -850,6 +1029,195 @@ func TestJSONHandling(t *testing.T) {
 	})
 }
 
+// TestJoinURL tests base URL and path joining
+func TestJoinURL(t *testing.T) {
+	tests := []struct {
+		name     string
+		base     string
+		path     string
+		expected string
+	}{
+		{"SimpleJoin", "https://api.example.com", "users", "https://api.example.com/users"},
+		{"LeadingAndTrailingSlashes", "https://api.example.com/", "/users", "https://api.example.com/users"},
+		{"RepeatedSlashes", "https://api.example.com//", "//users/1", "https://api.example.com/users/1"},
+		{"EmptyPath", "https://api.example.com/", "", "https://api.example.com"},
+		{"SlashOnlyPath", "https://api.example.com", "/", "https://api.example.com"},
+		{"PathWithQuery", "https://api.example.com", "/users?limit=5", "https://api.example.com/users?limit=5"},
+		{"QueryOnlyPath", "https://api.example.com/users/", "?limit=5", "https://api.example.com/users?limit=5"},
+		{"BaseWithPath", "https://api.example.com/v1/", "/users", "https://api.example.com/v1/users"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := joinURL(tt.base, tt.path); got != tt.expected {
+				t.Errorf("Expected %s, got %s", tt.expected, got)
+			}
+		})
+	}
+}
+
+// TestGetWithRetry tests retry behavior and Retry-After handling
+func TestGetWithRetry(t *testing.T) {
+	newClient := func(t *testing.T, baseURL string) *RestClient {
+		config := Config{
+			BaseURL:  baseURL,
+			Timeout:  30,
+			AuthType: "none",
+		}
+
+		configData, _ := json.Marshal(config)
+		tmpFile := "test_retry_config.json"
+		os.WriteFile(tmpFile, configData, 0644)
+		defer os.Remove(tmpFile)
+
+		client, err := NewRestClient(tmpFile)
+		if err != nil {
+			t.Fatalf("Failed to create client: %v", err)
+		}
+		return client
+	}
+
+	policy := RetryPolicy{
+		MaxRetries:     3,
+		InitialBackoff: 10 * time.Millisecond,
+		Multiplier:     2.0,
+	}
+
+	t.Run("RespectsRetryAfter", func(t *testing.T) {
+		attempts := 0
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			attempts++
+			if attempts == 1 {
+				w.Header().Set("Retry-After", "1")
+				w.WriteHeader(http.StatusTooManyRequests)
+				return
+			}
+			w.WriteHeader(200)
+			w.Write([]byte(`{"status":"ok"}`))
+		}))
+		defer server.Close()
+
+		client := newClient(t, server.URL)
+
+		start := time.Now()
+		resp, err := client.GetWithRetry("/limited", nil, policy)
+		elapsed := time.Since(start)
+
+		if err != nil {
+			t.Fatalf("Request failed: %v", err)
+		}
+		if resp.StatusCode != 200 {
+			t.Errorf("Expected status 200, got %d", resp.StatusCode)
+		}
+		if attempts != 2 {
+			t.Errorf("Expected 2 attempts, got %d", attempts)
+		}
+		if elapsed < time.Second {
+			t.Errorf("Expected to wait at least 1s per Retry-After, waited %v", elapsed)
+		}
+	})
+
+	t.Run("NoRetryOnClientError", func(t *testing.T) {
+		attempts := 0
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			attempts++
+			w.WriteHeader(http.StatusNotFound)
+		}))
+		defer server.Close()
+
+		client := newClient(t, server.URL)
+
+		resp, err := client.GetWithRetry("/missing", nil, policy)
+		if err != nil {
+			t.Fatalf("Request failed: %v", err)
+		}
+		if resp.StatusCode != 404 {
+			t.Errorf("Expected status 404, got %d", resp.StatusCode)
+		}
+		if attempts != 1 {
+			t.Errorf("Expected 1 attempt, got %d", attempts)
+		}
+	})
+
+	t.Run("ExhaustsRetriesOnServerError", func(t *testing.T) {
+		attempts := 0
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			attempts++
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}))
+		defer server.Close()
+
+		client := newClient(t, server.URL)
+
+		resp, err := client.GetWithRetry("/unavailable", nil, policy)
+		if err == nil {
+			t.Fatal("Expected error after exhausting retries, got nil")
+		}
+		if resp == nil || resp.StatusCode != 503 {
+			t.Errorf("Expected last response with status 503, got %v", resp)
+		}
+		if attempts != policy.MaxRetries+1 {
+			t.Errorf("Expected %d attempts, got %d", policy.MaxRetries+1, attempts)
+		}
+	})
+}
+
+// TestRequestTimeout tests that a per-request timeout overrides the config-level one
+func TestRequestTimeout(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(200 * time.Millisecond)
+		w.WriteHeader(200)
+		w.Write([]byte(`{"status":"ok"}`))
+	}))
+	defer server.Close()
+
+	config := Config{
+		BaseURL:  server.URL,
+		Timeout:  5,
+		AuthType: "none",
+	}
+
+	configData, _ := json.Marshal(config)
+	tmpFile := "test_timeout_config.json"
+	os.WriteFile(tmpFile, configData, 0644)
+	defer os.Remove(tmpFile)
+
+	client, err := NewRestClient(tmpFile)
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+
+	t.Run("PerRequestTimeoutFires", func(t *testing.T) {
+		start := time.Now()
+		_, err := client.Execute(Request{
+			Method:  "GET",
+			Path:    "/slow",
+			Timeout: 50 * time.Millisecond,
+		})
+		elapsed := time.Since(start)
+
+		if err == nil {
+			t.Fatal("Expected timeout error, got nil")
+		}
+		if elapsed >= time.Duration(config.Timeout)*time.Second {
+			t.Errorf("Expected per-request timeout to fire before the client timeout, took %v", elapsed)
+		}
+	})
+
+	t.Run("ClientTimeoutApplies", func(t *testing.T) {
+		resp, err := client.Execute(Request{
+			Method: "GET",
+			Path:   "/slow",
+		})
+		if err != nil {
+			t.Fatalf("Request failed: %v", err)
+		}
+		if resp.StatusCode != 200 {
+			t.Errorf("Expected status 200, got %d", resp.StatusCode)
+		}
+	})
+}
+
 // BenchmarkRestClient benchmarks the REST client performance
 func BenchmarkRestClient(b *testing.B) {
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {